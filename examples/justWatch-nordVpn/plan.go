@@ -0,0 +1,166 @@
+package main
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "41.neocities.org/verde/nordVpn"
+   "41.neocities.org/verde/planner"
+   "context"
+   "errors"
+   "flag"
+   "fmt"
+   "io"
+   "log"
+   "net/http"
+   "net/url"
+   "os"
+   "os/exec"
+   "os/signal"
+   "path/filepath"
+   "slices"
+   "strings"
+   "time"
+)
+
+func main() {
+   log.SetFlags(log.Ltime)
+   ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+   defer stop()
+   err := new(client).do(ctx)
+   if err != nil && ctx.Err() == nil {
+      log.Fatal(err)
+   }
+}
+
+type client struct {
+   address    string
+   providers  string
+   types      string
+   preference string
+   proxies    int
+   sleep      time.Duration
+}
+
+func (c *client) do(ctx context.Context) error {
+   flag.StringVar(&c.address, "a", "", "address")
+   flag.StringVar(&c.providers, "p", "", "providers, for example netflix,mubi")
+   flag.StringVar(&c.types, "m", "FLATRATE,FREE,ADS", "monetization types")
+   flag.StringVar(&c.preference, "c", "", "preferred countries, for example US,GB")
+   flag.IntVar(&c.proxies, "n", 3, "proxies per country")
+   flag.DurationVar(&c.sleep, "s", 99*time.Millisecond, "sleep")
+   flag.Parse()
+   if c.address == "" || c.providers == "" {
+      flag.Usage()
+      return nil
+   }
+   cache, err := os.UserCacheDir()
+   if err != nil {
+      return err
+   }
+   // written by examples/nordVpn -w
+   data, err := read_file(filepath.Join(cache, "nordVpn/nordVpn.json"))
+   if err != nil {
+      return err
+   }
+   plan := planner.Planner{
+      JustWatch: &justWatch.Client{
+         Http: &http.Client{
+            Transport: &http.Transport{
+               Proxy: func(req *http.Request) (*url.URL, error) {
+                  if req.URL.Path != "/graphql" {
+                     log.Println(req.Method, req.URL)
+                  }
+                  return nil, nil
+               },
+            },
+         },
+      },
+   }
+   plan.Servers, err = nordVpn.ReadServers(data)
+   if err != nil {
+      return err
+   }
+   plan.Username, err = output(ctx, "credential", "-h=api.nordvpn.com", "-k=username")
+   if err != nil {
+      return err
+   }
+   plan.Password, err = output(ctx, "credential", "-h=api.nordvpn.com")
+   if err != nil {
+      return err
+   }
+   plan.Fetch = &justWatch.FetchOptions{
+      Workers: 4,
+      Limiter: &justWatch.Limiter{Interval: c.sleep},
+   }
+   countries, err := plan.Plan(ctx, c.address, &planner.Options{
+      Providers:    strings.Split(c.providers, ","),
+      Monetization: strings.Split(c.types, ","),
+      Preference:   split(c.preference),
+      Proxies:      c.proxies,
+   })
+   if ctx.Err() != nil {
+      return ctx.Err()
+   }
+   if err != nil {
+      log.Print(err)
+   }
+   for i, country := range countries {
+      if i >= 1 {
+         fmt.Println()
+      }
+      fmt.Printf("%v (%v)", country.Name, country.Code)
+      for _, provider := range providers(country.Offers) {
+         fmt.Print(" ", provider)
+      }
+      fmt.Println()
+      for _, proxy := range country.Proxies {
+         fmt.Println(proxy)
+      }
+   }
+   return nil
+}
+
+// unique clear names, in order
+func providers(offers []*justWatch.EnrichedOffer) []string {
+   var names []string
+   for _, offer := range offers {
+      name := offer.Offer.Package.ClearName
+      if !slices.Contains(names, name) {
+         names = append(names, name)
+      }
+   }
+   return names
+}
+
+func split(value string) []string {
+   return strings.FieldsFunc(value, func(r rune) bool {
+      return r == ','
+   })
+}
+
+func read_file(name string) ([]byte, error) {
+   file, err := os.Open(name)
+   if err != nil {
+      return nil, err
+   }
+   defer file.Close()
+   info, err := file.Stat()
+   if err != nil {
+      return nil, err
+   }
+   if time.Since(info.ModTime()) >= 24*time.Hour {
+      return nil, errors.New("ModTime")
+   }
+   return io.ReadAll(file)
+}
+
+func output(ctx context.Context, name string, arg ...string) (string, error) {
+   var data strings.Builder
+   command := exec.CommandContext(ctx, name, arg...)
+   command.Stdout = &data
+   log.Println("Run", command.Args)
+   err := command.Run()
+   if err != nil {
+      return "", err
+   }
+   return data.String(), nil
+}
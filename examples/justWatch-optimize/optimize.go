@@ -0,0 +1,179 @@
+package main
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "context"
+   "encoding/json"
+   "errors"
+   "flag"
+   "fmt"
+   "log"
+   "net/http"
+   "net/url"
+   "os"
+   "os/signal"
+   "strconv"
+   "strings"
+   "time"
+)
+
+func main() {
+   log.SetFlags(log.Ltime)
+   var c client
+   c.justWatch.Http = &http.Client{
+      Transport: &http.Transport{
+         Proxy: func(req *http.Request) (*url.URL, error) {
+            log.Println(req.Method, req.URL)
+            return nil, nil
+         },
+      },
+   }
+   retry := justWatch.DefaultRetryPolicy
+   c.justWatch.Retry = &retry
+   cache, err := justWatch.NewFileCache()
+   if err != nil {
+      log.Fatal(err)
+   }
+   c.justWatch.Cache = cache
+   ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+   defer stop()
+   err = c.do(ctx)
+   if err != nil && ctx.Err() == nil {
+      log.Fatal(err)
+   }
+}
+
+type client struct {
+   country   string
+   types     string
+   prices    string
+   list      string
+   exact     int
+   sleep     time.Duration
+   justWatch justWatch.Client
+}
+
+func (c *client) do(ctx context.Context) error {
+   flag.StringVar(&c.country, "c", "US", "country")
+   flag.StringVar(&c.types, "m", "FLATRATE,FREE,ADS", "monetization types")
+   flag.StringVar(&c.prices, "p", "", "monthly prices, for example netflix=15.49,mubi=14.99")
+   flag.StringVar(&c.list, "l", "", "JSON file with a list of title URLs")
+   flag.IntVar(&c.exact, "x", 12, "exact solver up to this many providers")
+   flag.DurationVar(&c.sleep, "s", 99*time.Millisecond, "sleep")
+   flag.Parse()
+   addresses := flag.Args()
+   if c.list != "" {
+      data, err := os.ReadFile(c.list)
+      if err != nil {
+         return err
+      }
+      var values []string
+      err = json.Unmarshal(data, &values)
+      if err != nil {
+         return err
+      }
+      addresses = append(addresses, values...)
+   }
+   if len(addresses) == 0 {
+      flag.Usage()
+      return nil
+   }
+   opts := justWatch.OptimizeOptions{
+      Country:      strings.ToUpper(c.country),
+      Monetization: strings.Split(c.types, ","),
+      Exact:        c.exact,
+   }
+   if c.prices != "" {
+      var err error
+      opts.Prices, err = parse_prices(c.prices)
+      if err != nil {
+         return err
+      }
+   }
+   var offers []*justWatch.EnrichedOffer
+   limiter := justWatch.Limiter{Interval: c.sleep}
+   for _, address := range addresses {
+      value, err := c.offers(ctx, address, opts.Country, &limiter)
+      if ctx.Err() != nil {
+         return ctx.Err()
+      }
+      if err != nil {
+         log.Print(err)
+         continue
+      }
+      offers = append(offers, value...)
+   }
+   plan, err := justWatch.Optimize(offers, &opts)
+   if err != nil {
+      return err
+   }
+   for _, name := range plan.Unpriced {
+      log.Println("no price for", name)
+   }
+   for _, value := range plan.Subscriptions {
+      fmt.Printf("%v (%v)", value.ClearName, value.TechnicalName)
+      if opts.Prices != nil {
+         fmt.Printf(" %.2f", value.Price)
+      }
+      fmt.Printf(", %v titles\n", len(value.Titles))
+   }
+   if opts.Prices != nil {
+      fmt.Printf("total %.2f\n", plan.Price)
+   }
+   fmt.Printf("\n%v of %v titles covered\n", plan.Covered(), len(plan.Titles))
+   for _, title := range plan.Titles {
+      providers := strings.Join(title.Providers, ",")
+      if providers == "" {
+         providers = "-"
+      }
+      fmt.Println(title.Title, providers)
+   }
+   return nil
+}
+
+// offers of one title in country
+func (c *client) offers(
+   ctx context.Context, address, country string, limiter *justWatch.Limiter,
+) ([]*justWatch.EnrichedOffer, error) {
+   url_path, err := justWatch.GetPath(address)
+   if err != nil {
+      return nil, err
+   }
+   content, err := c.justWatch.ContentContext(ctx, url_path)
+   if err != nil {
+      return nil, err
+   }
+   region, err := justWatch.LookupRegionSet(country)
+   if err != nil {
+      return nil, err
+   }
+   offers, err := c.justWatch.FetchOffers(
+      ctx, content, justWatch.EnUs, &justWatch.FetchOptions{
+         Limiter: limiter, Region: region,
+      },
+   )
+   if err != nil {
+      return nil, err
+   }
+   if len(offers) == 0 {
+      log.Println("no offers in", country, url_path)
+   }
+   return justWatch.Deduplicate(offers), nil
+}
+
+// netflix=15.49,mubi=14.99
+func parse_prices(value string) (map[string]float64, error) {
+   prices := map[string]float64{}
+   for _, pair := range strings.Split(value, ",") {
+      name, price, ok := strings.Cut(pair, "=")
+      if !ok {
+         return nil, errors.New("expected name=price: " + pair)
+      }
+      var err error
+      prices[name], err = strconv.ParseFloat(price, 64)
+      if err != nil {
+         return nil, err
+      }
+   }
+   return prices, nil
+}
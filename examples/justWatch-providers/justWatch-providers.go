@@ -1,42 +1,56 @@
 package main
 
 import (
-   "bytes"
+   "41.neocities.org/verde/justWatch"
    "cmp"
+   "context"
    "encoding/json"
    "flag"
    "fmt"
-   "io"
    "log"
    "net/http"
    "net/url"
    "os"
+   "os/signal"
    "slices"
    "strings"
+   "time"
 )
 
 func main() {
    log.SetFlags(log.Ltime)
-   http.DefaultTransport = &http.Transport{
-      DisableKeepAlives: true, // github.com/golang/go/issues/25793
-      Proxy: func(req *http.Request) (*url.URL, error) {
-         log.Println(req.Method, req.URL)
-         return nil, nil
+   client := &justWatch.Client{
+      Http: &http.Client{
+         Transport: &http.Transport{
+            DisableKeepAlives: true, // github.com/golang/go/issues/25793
+            Proxy: func(req *http.Request) (*url.URL, error) {
+               log.Println(req.Method, req.URL)
+               return nil, nil
+            },
+         },
       },
    }
 
+   ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+   defer stop()
+
    countryCode := flag.String("a", "", "Country code to process (e.g., 'us')")
    jsonFile := flag.String("b", "", "JSON file with a list of provider URLs")
+   matrix := flag.Bool("m", false, "provider by country matrix of every locale")
+   format := flag.String("format", "json", "matrix format: csv or json")
+   matrixFile := flag.String("i", "", "read the matrix from a JSON file")
+   have := flag.String("have", "", "countries with all providers (e.g. 'mubi,criterion-channel')")
+   workers := flag.Int("w", 4, "matrix workers")
    flag.Parse()
 
-   if *countryCode == "" && *jsonFile == "" {
+   if *countryCode == "" && *jsonFile == "" && !*matrix && *have == "" {
       flag.Usage()
       return
    }
 
    // Handle -a flag
    if *countryCode != "" {
-      providers, err := processCountry(*countryCode, nil)
+      providers, err := processCountry(ctx, client, *countryCode, nil)
       if err != nil {
          log.Fatalf("failed to process country %s: %v", *countryCode, err)
       }
@@ -47,13 +61,63 @@ func main() {
 
    // Handle -b flag
    if *jsonFile != "" {
-      processJSONFile(*jsonFile)
+      processJSONFile(ctx, client, *jsonFile)
+   }
+
+   // Handle -m and -have flags
+   if *matrix || *have != "" {
+      coverage, err := loadCoverage(ctx, client, *matrixFile, *workers)
+      if err != nil {
+         log.Fatal(err)
+      }
+      if *have != "" {
+         for _, country := range coverage.Where(strings.Split(*have, ",")...) {
+            fmt.Println(country)
+         }
+      } else if err := writeCoverage(coverage, *format); err != nil {
+         log.Fatal(err)
+      }
+   }
+}
+
+// loadCoverage reads the matrix from filename, or fetches it for every
+// locale in justWatch.EnUs if filename is empty. Countries that fail are
+// logged and left out.
+func loadCoverage(ctx context.Context, client *justWatch.Client, filename string, workers int) (*justWatch.Coverage, error) {
+   if filename != "" {
+      file, err := os.Open(filename)
+      if err != nil {
+         return nil, err
+      }
+      defer file.Close()
+      return justWatch.ReadCoverage(file)
+   }
+   coverage, err := client.FetchCoverage(ctx, justWatch.EnUs, &justWatch.FetchOptions{
+      Workers: workers,
+      Limiter: &justWatch.Limiter{Interval: 100 * time.Millisecond, Burst: workers},
+   })
+   if ctx.Err() != nil {
+      return nil, ctx.Err()
+   }
+   if err != nil {
+      log.Print(err)
+   }
+   return coverage, nil
+}
+
+func writeCoverage(coverage *justWatch.Coverage, format string) error {
+   switch format {
+   case "csv":
+      return coverage.Csv(os.Stdout)
+   case "json":
+      return coverage.Json(os.Stdout)
    }
+   return fmt.Errorf("unknown format: %s", format)
 }
 
 // processJSONFile handles the logic for the -b flag: reading the file,
 // parsing URLs, sorting countries, and fetching/filtering provider slugs.
-func processJSONFile(filename string) {
+func processJSONFile(ctx context.Context, client *justWatch.Client, filename string) {
    file, err := os.ReadFile(filename)
    if err != nil {
       log.Fatalf("failed to read json file: %v", err)
@@ -64,20 +128,25 @@ func processJSONFile(filename string) {
       log.Fatalf("failed to unmarshal json file: %v", err)
    }
 
+   translations, err := client.FetchTranslations(ctx)
+   if err != nil {
+      log.Fatalf("failed to fetch translations: %v", err)
+   }
+
    countriesToProvidersList := make(map[string][]string)
    for _, providerURL := range providerURLs {
-      parsedURL, err := url.Parse(providerURL)
+      address, err := translations.Parse(providerURL)
       if err != nil {
-         log.Printf("failed to parse URL %s: %v", providerURL, err)
+         log.Print(err)
          continue
       }
-      pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
-      if len(pathParts) != 3 {
-         log.Printf("invalid provider URL format: %s", providerURL)
+      if address.Kind != "PROVIDER" {
+         log.Printf("not a provider URL: %s", providerURL)
          continue
       }
-      country, providerSlug := pathParts[0], pathParts[2]
-      countriesToProvidersList[country] = append(countriesToProvidersList[country], providerSlug)
+      countriesToProvidersList[address.Country] = append(
+         countriesToProvidersList[address.Country], address.Slug,
+      )
    }
 
    type CountryInfo struct {
@@ -114,7 +183,10 @@ func processJSONFile(filename string) {
          providerFilter[slug] = true
       }
 
-      foundSlugs, err := processCountry(countryInfo.Code, providerFilter)
+      foundSlugs, err := processCountry(ctx, client, countryInfo.Code, providerFilter)
+      if ctx.Err() != nil {
+         log.Fatal(ctx.Err())
+      }
       if err != nil {
          log.Printf("error processing country %s: %v", countryInfo.Code, err)
          continue
@@ -131,50 +203,16 @@ func processJSONFile(filename string) {
    }
 }
 
-// processCountry fetches and parses provider data for a given country.
+// processCountry fetches the provider catalog for a given country.
 // It returns an ordered slice of provider slugs that match the filter, or an error.
-func processCountry(countryCode string, providerFilter map[string]bool) ([]string, error) {
-   res, err := http.Get(fmt.Sprintf("https://www.justwatch.com/%s", countryCode))
-   if err != nil {
-      return nil, fmt.Errorf("failed to get URL for country %s: %w", countryCode, err)
-   }
-   defer res.Body.Close()
-
-   if res.StatusCode != 200 {
-      return nil, fmt.Errorf("request failed for country %s with status code: %d %s", countryCode, res.StatusCode, res.Status)
-   }
-
-   bodyBytes, err := io.ReadAll(res.Body)
+func processCountry(ctx context.Context, client *justWatch.Client, countryCode string, providerFilter map[string]bool) ([]string, error) {
+   providers, err := client.ProvidersContext(ctx, countryCode)
    if err != nil {
-      return nil, fmt.Errorf("failed to read response body for country %s: %w", countryCode, err)
-   }
-
-   _, after, found := bytes.Cut(bodyBytes, []byte("window.__DATA__="))
-   if !found {
-      return nil, fmt.Errorf("could not find 'window.__DATA__=' in the response body for country %s", countryCode)
-   }
-
-   jsonData, _, found := bytes.Cut(after, []byte("</script>"))
-   if !found {
-      return nil, fmt.Errorf("could not find closing '</script>' tag after the data for country %s", countryCode)
-   }
-
-   var result struct {
-      State struct {
-         Constant struct {
-            Providers []struct {
-               HasTitles bool
-               Slug      string
-            }
-         }
-      }
-   }
-   if err := json.Unmarshal(jsonData, &result); err != nil {
-      return nil, fmt.Errorf("failed to unmarshal JSON for country %s: %w", countryCode, err)
+      return nil, fmt.Errorf("failed to get providers for country %s: %w", countryCode, err)
    }
 
    var foundProviders []string
-   for _, provider := range result.State.Constant.Providers {
+   for _, provider := range providers {
       // If filter is nil (for -a flag) or the slug is in the filter, add it.
       if provider.HasTitles && (providerFilter == nil || providerFilter[provider.Slug]) {
          foundProviders = append(foundProviders, provider.Slug)
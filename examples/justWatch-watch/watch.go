@@ -0,0 +1,113 @@
+package main
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "41.neocities.org/verde/justWatch/watch"
+   "context"
+   "flag"
+   "log"
+   "net/http"
+   "net/url"
+   "os"
+   "os/signal"
+   "path/filepath"
+   "strings"
+   "time"
+)
+
+func main() {
+   log.SetFlags(log.Ltime)
+   var c client
+   c.justWatch.Http = &http.Client{
+      Transport: &http.Transport{
+         Proxy: func(req *http.Request) (*url.URL, error) {
+            if req.URL.Path != "/graphql" {
+               log.Println(req.Method, req.URL)
+            }
+            return nil, nil
+         },
+      },
+   }
+   retry := justWatch.DefaultRetryPolicy
+   c.justWatch.Retry = &retry
+   ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+   defer stop()
+   err := c.do(ctx)
+   if err != nil && ctx.Err() == nil {
+      log.Fatal(err)
+   }
+}
+
+type client struct {
+   watchlist string
+   state     string
+   interval  time.Duration
+   countries string
+   types     string
+   webhook   string
+   file      string
+   existing  bool
+   once      bool
+   justWatch justWatch.Client
+}
+
+func (c *client) do(ctx context.Context) error {
+   cache, err := os.UserCacheDir()
+   if err != nil {
+      return err
+   }
+   flag.StringVar(&c.watchlist, "w", "", "watchlist JSON file")
+   flag.StringVar(
+      &c.state, "state", filepath.Join(cache, "justWatch/watch.json"), "state",
+   )
+   flag.DurationVar(&c.interval, "i", 6*time.Hour, "interval")
+   flag.StringVar(&c.countries, "c", "", "countries, for example US,GB")
+   flag.StringVar(&c.types, "m", "", "monetization types, for example FLATRATE,FREE")
+   flag.StringVar(&c.webhook, "webhook", "", "webhook URL")
+   flag.StringVar(&c.file, "file", "", "append notifications to file")
+   flag.BoolVar(&c.existing, "existing", false, "notify about existing offers")
+   flag.BoolVar(&c.once, "once", false, "poll once and exit")
+   flag.Parse()
+   if c.watchlist == "" {
+      flag.Usage()
+      return nil
+   }
+   list, err := watch.ReadWatchlist(c.watchlist)
+   if err != nil {
+      return err
+   }
+   if c.countries != "" || c.types != "" {
+      list.Rules = append(list.Rules, watch.Rule{
+         Countries:         split(c.countries),
+         MonetizationTypes: split(c.types),
+      })
+   }
+   monitor := watch.Monitor{
+      Client:    &c.justWatch,
+      Fetch:     &justWatch.FetchOptions{Workers: 4},
+      Watchlist: list,
+      Sinks:     []watch.Sink{&watch.WriterSink{W: os.Stdout}},
+      StateFile: c.state,
+      Interval:  c.interval,
+      OnError: func(err error) {
+         log.Print(err)
+      },
+      NotifyExisting: c.existing,
+   }
+   if c.webhook != "" {
+      monitor.Sinks = append(monitor.Sinks, &watch.WebhookSink{Url: c.webhook})
+   }
+   if c.file != "" {
+      monitor.Sinks = append(monitor.Sinks, &watch.FileSink{Name: c.file})
+   }
+   if c.once {
+      return monitor.Poll(ctx)
+   }
+   return monitor.Run(ctx)
+}
+
+func split(value string) []string {
+   return strings.FieldsFunc(value, func(r rune) bool {
+      return r == ','
+   })
+}
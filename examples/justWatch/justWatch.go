@@ -2,113 +2,313 @@ package main
 
 import (
    "41.neocities.org/verde/justWatch"
+   "41.neocities.org/verde/justWatch/report"
    "bytes"
+   "context"
    "errors"
    "flag"
+   "fmt"
    "log"
    "net/http"
    "net/url"
    "os"
+   "os/signal"
    "path"
-   "strconv"
    "strings"
    "time"
 )
 
 func main() {
    log.SetFlags(log.Ltime)
-   http.DefaultTransport = &http.Transport{
-      DisableKeepAlives: true, // github.com/golang/go/issues/25793
-      Proxy: func(req *http.Request) (*url.URL, error) {
-         if req.URL.Path != "/graphql" {
-            if req.Method == "" {
-               req.Method = "GET"
+   var c client
+   c.justWatch.Http = &http.Client{
+      Transport: &http.Transport{
+         DisableKeepAlives: true, // github.com/golang/go/issues/25793
+         Proxy: func(req *http.Request) (*url.URL, error) {
+            if req.URL.Path != "/graphql" {
+               log.Println(req.Method, req.URL)
             }
-            log.Println(req.Method, req.URL)
-         }
-         return nil, nil
+            return nil, nil
+         },
       },
    }
-   err := new(client).do()
+   ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+   defer stop()
+   cache, err := justWatch.NewFileCache()
    if err != nil {
       log.Fatal(err)
    }
+   c.justWatch.Cache = cache
+   retry := justWatch.DefaultRetryPolicy
+   c.justWatch.Retry = &retry
+   retry.OnRetry = func(attempt int, delay time.Duration, err error) {
+      log.Println("retry", attempt, delay, err)
+   }
+   err = c.do(ctx)
+   if err != nil && ctx.Err() == nil {
+      log.Fatal(err)
+   }
 }
 
-func (c *client) do() error {
+func (c *client) do(ctx context.Context) error {
    flag.StringVar(&c.address, "a", "", "address")
+   flag.StringVar(&c.search, "q", "", "search, or title to fall back on with -i")
+   flag.StringVar(
+      &c.external, "i", "", "tt0099685, tmdb:movie/769 or tmdb:tv/1920",
+   )
+   flag.StringVar(&c.country, "c", "US", "search country")
    flag.DurationVar(&c.sleep, "s", 99*time.Millisecond, "sleep")
+   flag.IntVar(&c.workers, "w", 4, "workers")
+   flag.BoolVar(&c.justWatch.Refresh, "r", false, "refresh cache")
+   flag.StringVar(&c.format, "format", "markdown", "csv, html, json or markdown")
+   flag.StringVar(&c.snapshot, "snapshot", "", "also write offers to snapshot file")
+   flag.StringVar(&c.old, "old", "", "diff old snapshot")
+   flag.StringVar(&c.new, "new", "", "diff new snapshot")
+   flag.BoolVar(&c.seasons, "e", false, "season and episode offers")
+   flag.StringVar(
+      &c.region, "region", "", "only fetch countries in region, for example eu",
+   )
    flag.StringVar(&c.filters, "f", "BUY,CINEMA,FAST,RENT", "filters")
+   flag.StringVar(
+      &c.expression, "x", "", `filter expression, for example
+"country in (US,GB) and not monetization=RENT"`,
+   )
+   flag.StringVar(
+      &c.group, "g", "url", "group: country, monetization, provider, season or url",
+   )
+   flag.StringVar(&c.order, "order", "", "group order: alpha, length, season or size")
+   flag.StringVar(
+      &c.sort, "sort", "country",
+      "offer order: country, monetization, price, provider or season",
+   )
+   flag.StringVar(&c.rules, "rules", "", "canonical rules JSON file")
    flag.Parse()
-
+   var err error
+   justWatch.Canonical, err = justWatch.LoadCanonicalizer(c.rules)
+   if err != nil {
+      return err
+   }
    if c.address != "" {
-      return c.do_address()
+      return c.do_address(ctx)
+   }
+   if c.external != "" {
+      return c.do_external(ctx)
+   }
+   if c.old != "" && c.new != "" {
+      return c.do_diff()
+   }
+   if c.search != "" {
+      return c.do_search(ctx)
    }
    flag.Usage()
    return nil
 }
 
 type client struct {
-   address string
-   filters string
-   sleep   time.Duration
+   address    string
+   search     string
+   external   string
+   country    string
+   filters    string
+   expression string
+   sleep      time.Duration
+   workers    int
+   seasons    bool
+   region     string
+   format     string
+   snapshot   string
+   old        string
+   new        string
+   rules      string
+   group      string
+   order      string
+   sort       string
+   justWatch  justWatch.Client
+}
+
+func (c *client) do_search(ctx context.Context) error {
+   titles, err := c.justWatch.SearchContext(ctx, c.search, c.country)
+   if err != nil {
+      return err
+   }
+   for _, title := range titles {
+      fmt.Printf(
+         "%v (%v) %v\nhttps://www.justwatch.com%v\n\n",
+         title.Content.Title, title.Content.OriginalReleaseYear,
+         title.ObjectType, title.Content.FullPath,
+      )
+   }
+   return nil
+}
+
+func (c *client) do_external(ctx context.Context) error {
+   id, err := justWatch.ParseExternalId(c.external)
+   if err != nil {
+      return err
+   }
+   id.Title = c.search
+   title, content, err := c.justWatch.ResolveContext(ctx, id, c.country)
+   if err != nil {
+      return err
+   }
+   return c.do_content(ctx, title.Content.FullPath, content)
 }
 
-func (c *client) do_address() error {
+func (c *client) do_address(ctx context.Context) error {
    url_path, err := justWatch.GetPath(c.address)
    if err != nil {
       return err
    }
-   var content justWatch.Content
-   err = content.Fetch(url_path)
+   content, err := c.justWatch.ContentContext(ctx, url_path)
    if err != nil {
       return err
    }
-   var allEnrichedOffers []*justWatch.EnrichedOffer
-   for _, tag := range content.HrefLangTags {
-      locale, ok := justWatch.EnUs.Locale(&tag)
-      if !ok {
-         return errors.New("Locale")
-      }
-      log.Print(locale)
-      offers, err := tag.Offers(locale)
+   return c.do_content(ctx, url_path, content)
+}
+
+func (c *client) do_content(
+   ctx context.Context, url_path string, content *justWatch.Content,
+) error {
+   opts := justWatch.FetchOptions{
+      Workers: c.workers,
+      Limiter: &justWatch.Limiter{Interval: c.sleep},
+      Seasons: c.seasons,
+   }
+   if c.region != "" {
+      var err error
+      opts.Region, err = justWatch.LookupRegionSet(c.region)
       if err != nil {
          return err
       }
-      for _, offer := range offers {
-         allEnrichedOffers = append(allEnrichedOffers,
-            &justWatch.EnrichedOffer{Locale: locale, Offer: &offer},
-         )
-      }
-      time.Sleep(c.sleep)
+   }
+   allEnrichedOffers, err := c.justWatch.FetchOffers(
+      ctx, content, justWatch.EnUs, &opts,
+   )
+   if ctx.Err() != nil {
+      return ctx.Err()
+   }
+   if err != nil {
+      log.Print(err)
    }
    enrichedOffers := justWatch.Deduplicate(allEnrichedOffers)
+   // before -f and -x, so a diff sees RENT -> FLATRATE
+   if c.snapshot != "" {
+      err = write_snapshot(c.snapshot, enrichedOffers)
+      if err != nil {
+         return err
+      }
+   }
    enrichedOffers = justWatch.FilterOffers(
       enrichedOffers, strings.Split(c.filters, ",")...,
    )
-   sortedUrls, groupedOffers := justWatch.GroupAndSortByUrl(enrichedOffers)
-   data := &bytes.Buffer{}
-   for i, address := range sortedUrls {
-      if i >= 1 {
-         data.WriteString("\n\n")
-      }
-      data.WriteString("## ")
-      data.WriteString(address)
-      for _, enriched := range groupedOffers[address] {
-         data.WriteByte('\n')
-         data.WriteString("\ncountry = ")
-         data.WriteString(enriched.Locale.Country)
-         data.WriteString("\nname = ")
-         data.WriteString(enriched.Locale.CountryName)
-         data.WriteString("\nmonetization = ")
-         data.WriteString(enriched.Offer.MonetizationType)
-         if enriched.Offer.ElementCount >= 1 {
-            data.WriteString("\ncount = ")
-            data.WriteString(strconv.Itoa(enriched.Offer.ElementCount))
-         }
+   if c.expression != "" {
+      predicate, err := justWatch.ParseFilter(c.expression)
+      if err != nil {
+         return err
       }
+      enrichedOffers = justWatch.Filter(enrichedOffers, predicate)
+   }
+   grouping, err := c.grouping()
+   if err != nil {
+      return err
+   }
+   sortedUrls, groupedOffers := grouping.Group(enrichedOffers)
+   details, err := c.title_details(ctx, content)
+   if err != nil {
+      return err
+   }
+   extension, ok := report.Formats[c.format]
+   if !ok {
+      return errors.New("unknown format: " + c.format)
+   }
+   data := &bytes.Buffer{}
+   err = (&report.Report{
+      Title: details, Keys: sortedUrls, Groups: groupedOffers,
+   }).Write(data, c.format)
+   if err != nil {
+      return err
    }
-   name := path.Base(url_path) + ".md"
+   name := path.Base(url_path) + extension
    log.Println("WriteFile", name)
    return os.WriteFile(name, data.Bytes(), os.ModePerm)
 }
+
+// -g url with no other flags is GroupAndSortByUrl. -e -g season -sort season
+// is GroupBySeason
+func (c *client) grouping() (*justWatch.Grouping, error) {
+   grouping := justWatch.UrlGrouping
+   var ok bool
+   grouping.Key, ok = justWatch.KeyFuncs[c.group]
+   if !ok {
+      return nil, errors.New("unknown group: " + c.group)
+   }
+   switch c.group {
+   case "season":
+      grouping.Groups = justWatch.SeasonGroups
+   case "url":
+   default:
+      grouping.Groups = justWatch.KeyAlpha
+   }
+   if c.order != "" {
+      grouping.Groups, ok = justWatch.GroupOrders[c.order]
+      if !ok {
+         return nil, errors.New("unknown group order: " + c.order)
+      }
+   }
+   grouping.Offers, ok = justWatch.OfferOrders[c.sort]
+   if !ok {
+      return nil, errors.New("unknown sort: " + c.sort)
+   }
+   return &grouping, nil
+}
+
+func write_snapshot(name string, offers []*justWatch.EnrichedOffer) error {
+   log.Println("Create", name)
+   file, err := os.Create(name)
+   if err != nil {
+      return err
+   }
+   defer file.Close()
+   return justWatch.WriteSnapshot(file, offers)
+}
+
+func read_snapshot(name string) (*justWatch.Snapshot, error) {
+   file, err := os.Open(name)
+   if err != nil {
+      return nil, err
+   }
+   defer file.Close()
+   return justWatch.ReadSnapshot(file)
+}
+
+func (c *client) do_diff() error {
+   old, err := read_snapshot(c.old)
+   if err != nil {
+      return err
+   }
+   new, err := read_snapshot(c.new)
+   if err != nil {
+      return err
+   }
+   fmt.Println(old.Time.Format(time.DateOnly), "->", new.Time.Format(time.DateOnly))
+   _, err = justWatch.Diff(old.Offers, new.Offers).WriteTo(os.Stdout)
+   return err
+}
+
+// prefer en_US so the title is in English, else any known locale
+func (c *client) title_details(
+   ctx context.Context, content *justWatch.Content,
+) (*justWatch.TitleDetails, error) {
+   for _, tag := range content.HrefLangTags {
+      locale, ok := justWatch.EnUs.Locale(&tag)
+      if ok && locale.FullLocale == "en_US" {
+         return c.justWatch.TitleDetailsContext(ctx, &tag, locale)
+      }
+   }
+   for _, tag := range content.HrefLangTags {
+      if locale, ok := justWatch.EnUs.Lookup(&tag); ok {
+         return c.justWatch.TitleDetailsContext(ctx, &tag, locale)
+      }
+   }
+   return nil, nil
+}
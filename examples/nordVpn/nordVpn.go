@@ -2,6 +2,7 @@ package main
 
 import (
    "41.neocities.org/verde/nordVpn"
+   "context"
    "errors"
    "flag"
    "fmt"
@@ -11,6 +12,7 @@ import (
    "net/url"
    "os"
    "os/exec"
+   "os/signal"
    "path/filepath"
    "strings"
    "time"
@@ -27,13 +29,15 @@ func main() {
          return nil, nil
       },
    }
-   err := new(client).do()
-   if err != nil {
+   ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+   defer stop()
+   err := new(client).do(ctx)
+   if err != nil && ctx.Err() == nil {
       log.Fatal(err)
    }
 }
 
-func (c *client) do() error {
+func (c *client) do(ctx context.Context) error {
    var err error
    c.cache, err = os.UserCacheDir()
    if err != nil {
@@ -46,10 +50,10 @@ func (c *client) do() error {
    flag.StringVar(&c.country_code, "c", "", "country code")
    flag.Parse()
    if c.write {
-      return c.do_write()
+      return c.do_write(ctx)
    }
    if c.country_code != "" {
-      return c.do_country_code()
+      return c.do_country_code(ctx)
    }
    flag.Usage()
    return nil
@@ -76,8 +80,8 @@ func read_file(name string) ([]byte, error) {
    return io.ReadAll(file)
 }
 
-func (c *client) do_write() error {
-   data, err := nordVpn.WriteServers(0)
+func (c *client) do_write(ctx context.Context) error {
+   data, err := nordVpn.WriteServersContext(ctx, 0)
    if err != nil {
       return err
    }
@@ -92,9 +96,9 @@ type client struct {
    country_code string
 }
 
-func output(name string, arg ...string) (string, error) {
+func output(ctx context.Context, name string, arg ...string) (string, error) {
    var data strings.Builder
-   command := exec.Command(name, arg...)
+   command := exec.CommandContext(ctx, name, arg...)
    command.Stdout = &data
    log.Println("Run", command.Args)
    err := command.Run()
@@ -104,7 +108,7 @@ func output(name string, arg ...string) (string, error) {
    return data.String(), nil
 }
 
-func (c *client) do_country_code() error {
+func (c *client) do_country_code(ctx context.Context) error {
    data, err := read_file(c.cache)
    if err != nil {
       return err
@@ -113,11 +117,11 @@ func (c *client) do_country_code() error {
    if err != nil {
       return err
    }
-   username, err := output("credential", "-h=api.nordvpn.com", "-k=username")
+   username, err := output(ctx, "credential", "-h=api.nordvpn.com", "-k=username")
    if err != nil {
       return err
    }
-   password, err := output("credential", "-h=api.nordvpn.com")
+   password, err := output(ctx, "credential", "-h=api.nordvpn.com")
    if err != nil {
       return err
    }
@@ -0,0 +1,170 @@
+package justWatch
+
+import (
+   "context"
+   "net/url"
+   "slices"
+   "strings"
+)
+
+// Address is a parsed justwatch.com URL
+type Address struct {
+   Country string // GB
+   Kind    string // PROVIDER, MOVIE, SHOW or SHOW_SEASON
+   Slug    string // itvx, mulholland-drive
+   Path    string // /uk/provider/itvx
+}
+
+// UrlError reports why a URL is not a justwatch.com provider or title URL
+type UrlError struct {
+   Url    string
+   Reason string
+}
+
+func (u *UrlError) Error() string {
+   return "justWatch: " + u.Url + ": " + u.Reason
+}
+
+func prefix_country(prefix string) (string, bool) {
+   if prefix == "uk" {
+      return "GB", true
+   }
+   if prefix == "gb" {
+      return "", false
+   }
+   _, ok := CountryRegion(prefix)
+   return strings.ToUpper(prefix), ok
+}
+
+// Segments are the path segments of each kind in one locale
+type Segments struct {
+   Provider string // anbieter
+   Movie    string // film
+   Show     string // serie
+}
+
+func (s *Segments) kind(segment string) string {
+   switch segment {
+   case s.Provider:
+      return "PROVIDER"
+   case s.Movie:
+      return "MOVIE"
+   case s.Show:
+      return "SHOW"
+   }
+   return ""
+}
+
+// Translations are Segments by path prefix, such as uk
+type Translations map[string]Segments
+
+// Add sets the segment of kind, PROVIDER, MOVIE or SHOW, for the prefix of
+// every href of tags. tags are the HrefLangTags of one provider or title of
+// that kind
+func (t Translations) Add(kind string, tags []HrefLangTag) {
+   for _, tag := range tags {
+      parts := strings.Split(strings.Trim(tag.Href, "/"), "/")
+      if len(parts) < 3 {
+         continue
+      }
+      value := t[parts[0]]
+      switch kind {
+      case "PROVIDER":
+         value.Provider = parts[1]
+      case "MOVIE":
+         value.Movie = parts[1]
+      case "SHOW":
+         value.Show = parts[1]
+      }
+      t[parts[0]] = value
+   }
+}
+
+// a provider, movie and show that JustWatch has in every locale
+var translation_paths = []struct {
+   kind string
+   path string
+}{
+   {"PROVIDER", "/us/provider/netflix"},
+   {"MOVIE", "/us/movie/goodfellas"},
+   {"SHOW", "/us/tv-show/twin-peaks"},
+}
+
+// FetchTranslations is a wrapper for DefaultClient.FetchTranslations
+func FetchTranslations(ctx context.Context) (Translations, error) {
+   return DefaultClient.FetchTranslations(ctx)
+}
+
+// FetchTranslations derives the Translations of every locale from the
+// HrefLangTags of a known provider, movie and show
+func (c *Client) FetchTranslations(ctx context.Context) (Translations, error) {
+   translations := Translations{}
+   for _, value := range translation_paths {
+      content, err := c.ContentContext(ctx, value.path)
+      if err != nil {
+         return nil, err
+      }
+      translations.Add(value.kind, content.HrefLangTags)
+   }
+   return translations, nil
+}
+
+// ParseProviderUrl is KnownTranslations.Parse
+func ParseProviderUrl(rawUrl string) (*Address, error) {
+   return KnownTranslations.Parse(rawUrl)
+}
+
+// Parse parses provider URLs such as
+//  https://justwatch.com/se/leverantör/draken-films
+// and title URLs such as
+//  https://justwatch.com/us/movie/goodfellas
+//  https://justwatch.com/us/tv-show/twin-peaks/season-1
+// The second path segment must be the translation in t for the country.
+// Errors are *UrlError
+func (t Translations) Parse(rawUrl string) (*Address, error) {
+   fail := func(reason string) (*Address, error) {
+      return nil, &UrlError{rawUrl, reason}
+   }
+   parsed, err := url.Parse(strings.TrimSpace(rawUrl))
+   if err != nil {
+      return fail(err.Error())
+   }
+   if parsed.Scheme == "" {
+      return fail("scheme is missing")
+   }
+   host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+   if host != "justwatch.com" {
+      return fail("host is not justwatch.com")
+   }
+   parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+   if len(parts) < 3 || len(parts) > 4 {
+      return fail("expected /country/kind/slug")
+   }
+   if slices.Contains(parts, "") {
+      return fail("empty path segment")
+   }
+   country, ok := prefix_country(parts[0])
+   if !ok {
+      return fail("unknown country " + parts[0])
+   }
+   translation, ok := t[parts[0]]
+   if !ok {
+      return fail("no known translations for " + parts[0])
+   }
+   kind := translation.kind(parts[1])
+   if kind == "" {
+      return fail("unknown segment " + parts[1] + " for " + parts[0])
+   }
+   if len(parts) == 4 {
+      if kind != "SHOW" {
+         return fail("only a show can have a season")
+      }
+      kind = "SHOW_SEASON"
+   }
+   return &Address{
+      Country: country,
+      Kind:    kind,
+      Slug:    parts[2],
+      Path:    "/" + strings.Join(parts, "/"),
+   }, nil
+}
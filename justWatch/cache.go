@@ -0,0 +1,144 @@
+package justWatch
+
+import (
+   "crypto/sha256"
+   "encoding/hex"
+   "errors"
+   "io/fs"
+   "os"
+   "path/filepath"
+   "strings"
+   "time"
+)
+
+// Cache stores response bodies by key
+type Cache interface {
+   // Get returns false if key is missing or older than ttl
+   Get(key string, ttl time.Duration) ([]byte, bool, error)
+   Put(key string, data []byte) error
+}
+
+// DefaultTtl is used when Client.Ttl is nil. Keys are "/content/urls" and
+// GraphQL operation names. Endpoints that are missing are not cached
+var DefaultTtl = map[string]time.Duration{
+   "/content/urls":                24 * time.Hour,
+   "BackendConstantsFetcherQuery": 7 * 24 * time.Hour,
+   "GetProviders":                 24 * time.Hour,
+   "GetSearchTitles":              24 * time.Hour,
+   "GetShowSeasons":               12 * time.Hour,
+   "GetTitleDetails":              7 * 24 * time.Hour,
+   "GetUrlTitleDetails":           12 * time.Hour,
+}
+
+// FileCache is a Cache with one file per key
+type FileCache struct {
+   Dir string
+}
+
+// NewFileCache returns a FileCache in os.UserCacheDir
+func NewFileCache() (*FileCache, error) {
+   dir, err := os.UserCacheDir()
+   if err != nil {
+      return nil, err
+   }
+   return &FileCache{filepath.Join(dir, "justWatch")}, nil
+}
+
+func (f *FileCache) Get(key string, ttl time.Duration) ([]byte, bool, error) {
+   name := filepath.Join(f.Dir, key)
+   info, err := os.Stat(name)
+   if errors.Is(err, fs.ErrNotExist) {
+      return nil, false, nil
+   }
+   if err != nil {
+      return nil, false, err
+   }
+   if time.Since(info.ModTime()) >= ttl {
+      return nil, false, nil
+   }
+   data, err := os.ReadFile(name)
+   if err != nil {
+      return nil, false, err
+   }
+   return data, true, nil
+}
+
+// Put writes a temporary file and renames it, so a concurrent Get never sees
+// part of a file
+func (f *FileCache) Put(key string, data []byte) error {
+   err := os.MkdirAll(f.Dir, os.ModePerm)
+   if err != nil {
+      return err
+   }
+   temp, err := os.CreateTemp(f.Dir, key+".*.tmp")
+   if err != nil {
+      return err
+   }
+   _, err = temp.Write(data)
+   if err != nil {
+      temp.Close()
+      os.Remove(temp.Name())
+      return err
+   }
+   err = temp.Close()
+   if err != nil {
+      os.Remove(temp.Name())
+      return err
+   }
+   return os.Rename(temp.Name(), filepath.Join(f.Dir, key))
+}
+
+// "query GetUrlTitleDetails(" returns "GetUrlTitleDetails"
+func operation_name(query string) string {
+   fields := strings.FieldsFunc(query, func(r rune) bool {
+      return r == ' ' || r == '(' || r == '{' || r == '\n'
+   })
+   if len(fields) >= 2 {
+      return fields[1]
+   }
+   return ""
+}
+
+func (c *Client) ttl(endpoint string) time.Duration {
+   if c.Ttl != nil {
+      return c.Ttl[endpoint]
+   }
+   return DefaultTtl[endpoint]
+}
+
+// the base URL is part of the key, so clients of different servers do not
+// share responses
+func cache_key(base, endpoint string, request []byte) string {
+   hash := sha256.New()
+   hash.Write([]byte(base))
+   hash.Write([]byte{'\n'})
+   hash.Write([]byte(endpoint))
+   hash.Write([]byte{'\n'})
+   hash.Write(request)
+   return hex.EncodeToString(hash.Sum(nil))
+}
+
+// cache_get returns false if there is no Cache, the endpoint is not cached,
+// Refresh is set or the Cache fails. A failing Cache is treated as empty
+func (c *Client) cache_get(endpoint string, request []byte) ([]byte, bool) {
+   if c.Cache == nil || c.Refresh {
+      return nil, false
+   }
+   ttl := c.ttl(endpoint)
+   if ttl <= 0 {
+      return nil, false
+   }
+   data, ok, err := c.Cache.Get(cache_key(c.base_url(), endpoint, request), ttl)
+   if err != nil {
+      return nil, false
+   }
+   return data, ok
+}
+
+// cache_put ignores errors, since the response is good either way
+func (c *Client) cache_put(endpoint string, request, data []byte) {
+   if c.Cache == nil || c.ttl(endpoint) <= 0 {
+      return
+   }
+   c.Cache.Put(cache_key(c.base_url(), endpoint, request), data)
+}
@@ -0,0 +1,153 @@
+package justWatch
+
+import (
+   _ "embed"
+   "encoding/json"
+   "errors"
+   "fmt"
+   "io/fs"
+   "net/url"
+   "os"
+   "path/filepath"
+   "regexp"
+   "strings"
+)
+
+//go:embed canonical.json
+var canonical_json []byte
+
+// Rule is one step of URL canonicalization. Kind is one of
+//  param_equals  remove query parameter Key if its first value is Value
+//  param         remove query parameter Key
+//  param_prefix  remove query parameters starting with Key, such as utm_
+//  host          replace host Key with Value
+//  host_prefix   remove Key, such as www., from the start of the host
+//  path          replace matches of regular expression Key in the path with
+//                Value
+type Rule struct {
+   Date  string `json:"date"` // when the rule was added, 2026-03-08
+   Kind  string `json:"kind"`
+   Host  string `json:"host,omitempty"` // empty for every host
+   Key   string `json:"key"`
+   Value string `json:"value,omitempty"`
+   path  *regexp.Regexp
+}
+
+func (r *Rule) compile() error {
+   switch r.Kind {
+   case "param_equals", "param", "param_prefix", "host", "host_prefix":
+      return nil
+   case "path":
+      var err error
+      r.path, err = regexp.Compile(r.Key)
+      return err
+   }
+   return errors.New("unknown rule kind: " + r.Kind)
+}
+
+func (r *Rule) apply(address *url.URL, query url.Values) {
+   if r.Host != "" && r.Host != address.Host {
+      return
+   }
+   switch r.Kind {
+   case "param_equals":
+      // Get returns the first value, or "" if the key is missing
+      if query.Get(r.Key) == r.Value {
+         delete(query, r.Key)
+      }
+   case "param":
+      delete(query, r.Key)
+   case "param_prefix":
+      for key := range query {
+         if strings.HasPrefix(key, r.Key) {
+            delete(query, key)
+         }
+      }
+   case "host":
+      if address.Host == r.Key {
+         address.Host = r.Value
+      }
+   case "host_prefix":
+      address.Host = strings.TrimPrefix(address.Host, r.Key)
+   case "path":
+      address.Path = r.path.ReplaceAllString(address.Path, r.Value)
+      address.RawPath = ""
+   }
+}
+
+// ReadRules parses a JSON array of rules
+func ReadRules(data []byte) ([]Rule, error) {
+   var rules []Rule
+   err := json.Unmarshal(data, &rules)
+   if err != nil {
+      return nil, err
+   }
+   for i := range rules {
+      err = rules[i].compile()
+      if err != nil {
+         return nil, fmt.Errorf("rule %v %v: %w", i, rules[i].Date, err)
+      }
+   }
+   return rules, nil
+}
+
+// Canonicalizer applies rules in order
+type Canonicalizer struct {
+   Rules []Rule
+}
+
+// Canonical is used by getUrlGroupingKey. It starts with the embedded
+// canonical.json
+var Canonical = func() *Canonicalizer {
+   rules, err := ReadRules(canonical_json)
+   if err != nil {
+      panic(err)
+   }
+   return &Canonicalizer{rules}
+}()
+
+// LoadCanonicalizer returns the embedded rules followed by the rules in name.
+// If name is empty, justWatch/canonical.json in os.UserConfigDir is used. A
+// missing file is not an error
+func LoadCanonicalizer(name string) (*Canonicalizer, error) {
+   if name == "" {
+      dir, err := os.UserConfigDir()
+      if err != nil {
+         return nil, err
+      }
+      name = filepath.Join(dir, "justWatch", "canonical.json")
+   }
+   rules, err := ReadRules(canonical_json)
+   if err != nil {
+      return nil, err
+   }
+   data, err := os.ReadFile(name)
+   if errors.Is(err, fs.ErrNotExist) {
+      return &Canonicalizer{rules}, nil
+   }
+   if err != nil {
+      return nil, err
+   }
+   user_rules, err := ReadRules(data)
+   if err != nil {
+      return nil, fmt.Errorf("%v: %w", name, err)
+   }
+   return &Canonicalizer{append(rules, user_rules...)}, nil
+}
+
+// Key returns the canonical form of rawUrl, or rawUrl if it does not parse
+func (c *Canonicalizer) Key(rawUrl string) string {
+   trimmedUrl := strings.TrimSuffix(rawUrl, "\n")
+   parsed, err := url.Parse(trimmedUrl)
+   if err != nil {
+      return trimmedUrl
+   }
+   query := parsed.Query()
+   for _, rule := range c.Rules {
+      rule.apply(parsed, query)
+   }
+   if parsed.RawQuery != "" {
+      parsed.RawQuery = query.Encode()
+   }
+   return parsed.String()
+}
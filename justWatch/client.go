@@ -0,0 +1,291 @@
+package justWatch
+
+import (
+   "bytes"
+   "context"
+   "encoding/base64"
+   "encoding/json"
+   "errors"
+   "fmt"
+   "io"
+   "net/http"
+   "net/url"
+   "strings"
+   "time"
+)
+
+// Client holds everything needed to talk to apis.justwatch.com. The zero
+// value is ready to use
+type Client struct {
+   Http     *http.Client // nil for http.DefaultClient
+   BaseUrl  string       // empty for https://apis.justwatch.com
+   WebUrl   string       // empty for https://www.justwatch.com
+   DeviceId string       // empty for an all zero id
+   Header   http.Header  // added to every request
+   Retry    *RetryPolicy // nil for no retries
+   Cache    Cache        // nil for no cache
+   // Ttl is how long each endpoint is cached, nil for DefaultTtl
+   Ttl map[string]time.Duration
+   // Refresh skips reading the cache. Responses are still written to it
+   Refresh bool
+}
+
+// DefaultClient is used by the package level functions
+var DefaultClient Client
+
+func (c *Client) http_client() *http.Client {
+   if c.Http != nil {
+      return c.Http
+   }
+   return http.DefaultClient
+}
+
+func (c *Client) device_id() string {
+   if c.DeviceId != "" {
+      return c.DeviceId
+   }
+   return base64.RawStdEncoding.EncodeToString(make([]byte, 16))
+}
+
+func (c *Client) new_request(
+   ctx context.Context, method, path string, body []byte,
+) (*http.Request, error) {
+   return c.new_request_at(ctx, c.base_url(), method, path, body)
+}
+
+func (c *Client) base_url() string {
+   if c.BaseUrl != "" {
+      return c.BaseUrl
+   }
+   return "https://apis.justwatch.com"
+}
+
+func (c *Client) new_request_at(
+   ctx context.Context, base, method, path string, body []byte,
+) (*http.Request, error) {
+   address, err := url.JoinPath(base, path)
+   if err != nil {
+      return nil, err
+   }
+   req, err := http.NewRequestWithContext(
+      ctx, method, address, bytes.NewReader(body),
+   )
+   if err != nil {
+      return nil, err
+   }
+   for key, values := range c.Header {
+      for _, value := range values {
+         req.Header.Add(key, value)
+      }
+   }
+   return req, nil
+}
+
+// do sends req, retrying as c.Retry allows, and returns the body of the
+// first 200 response
+func (c *Client) do(req *http.Request) ([]byte, error) {
+   for attempt := 1; ; attempt++ {
+      data, err := c.do_once(req)
+      if err == nil {
+         return data, nil
+      }
+      if c.Retry == nil || !c.Retry.retry(attempt, err) {
+         return nil, err
+      }
+      delay := c.Retry.delay(attempt, err)
+      if c.Retry.OnRetry != nil {
+         c.Retry.OnRetry(attempt, delay, err)
+      }
+      timer := time.NewTimer(delay)
+      select {
+      case <-req.Context().Done():
+         timer.Stop()
+         return nil, req.Context().Err()
+      case <-timer.C:
+      }
+      req.Body, err = req.GetBody()
+      if err != nil {
+         return nil, err
+      }
+   }
+}
+
+func (c *Client) do_once(req *http.Request) ([]byte, error) {
+   resp, err := c.http_client().Do(req)
+   if err != nil {
+      return nil, err
+   }
+   defer resp.Body.Close()
+   data, err := io.ReadAll(resp.Body)
+   if err != nil {
+      return nil, err
+   }
+   if resp.StatusCode != http.StatusOK {
+      return nil, &StatusError{
+         StatusCode: resp.StatusCode,
+         Status:     resp.Status,
+         Body:       string(data),
+         RetryAfter: parse_retry_after(resp.Header.Get("retry-after")),
+      }
+   }
+   return data, nil
+}
+
+func (c *Client) graphql(
+   ctx context.Context, query string, variables map[string]string, value any,
+) error {
+   request, err := json.Marshal(map[string]any{
+      "query": query, "variables": variables,
+   })
+   if err != nil {
+      return err
+   }
+   endpoint := operation_name(query)
+   data, cached := c.cache_get(endpoint, request)
+   if !cached {
+      req, err := c.new_request(ctx, "POST", "/graphql", request)
+      if err != nil {
+         return err
+      }
+      req.Header.Set("content-type", "application/json")
+      req.Header.Set("device-id", c.device_id())
+      data, err = c.do(req)
+      if err != nil {
+         return err
+      }
+   }
+   result := struct {
+      Data   any
+      Errors []*GraphQLError
+   }{Data: value}
+   err = json.Unmarshal(data, &result)
+   if err != nil {
+      return err
+   }
+   switch len(result.Errors) {
+   case 0:
+      if !cached {
+         c.cache_put(endpoint, request, data)
+      }
+      return nil
+   case 1:
+      return result.Errors[0]
+   }
+   errs := make([]error, len(result.Errors))
+   for i, graphql_err := range result.Errors {
+      errs[i] = graphql_err
+   }
+   return errors.Join(errs...)
+}
+
+// GraphQLError is an entry of the errors array JustWatch returns, even with
+// status 200
+type GraphQLError struct {
+   Message    string
+   Path       []any
+   Extensions map[string]any
+}
+
+func (g *GraphQLError) Error() string {
+   var data strings.Builder
+   data.WriteString("graphql: ")
+   data.WriteString(g.Message)
+   if len(g.Path) >= 1 {
+      data.WriteString(" path=")
+      for i, elem := range g.Path {
+         if i >= 1 {
+            data.WriteByte('.')
+         }
+         fmt.Fprint(&data, elem)
+      }
+   }
+   if code, ok := g.Extensions["code"]; ok {
+      fmt.Fprint(&data, " code=", code)
+   }
+   return data.String()
+}
+
+// Content returns the href lang tags for a title path such as
+// /us/movie/goodfellas
+func (c *Client) Content(path string) (*Content, error) {
+   return c.ContentContext(context.Background(), path)
+}
+
+func (c *Client) ContentContext(ctx context.Context, path string) (*Content, error) {
+   const endpoint = "/content/urls"
+   request := []byte(url.Values{"path": {path}}.Encode())
+   data, cached := c.cache_get(endpoint, request)
+   if !cached {
+      req, err := c.new_request(ctx, "GET", endpoint, nil)
+      if err != nil {
+         return nil, err
+      }
+      req.URL.RawQuery = string(request)
+      data, err = c.do(req)
+      if err != nil {
+         return nil, err
+      }
+   }
+   var content Content
+   err := json.Unmarshal(data, &content)
+   if err != nil {
+      return nil, err
+   }
+   if !cached {
+      c.cache_put(endpoint, request, data)
+   }
+   return &content, nil
+}
+
+// Hello returns the locales JustWatch knows about, with country names in the
+// given language
+func (c *Client) Hello(language string) (Locales, error) {
+   return c.HelloContext(context.Background(), language)
+}
+
+func (c *Client) HelloContext(ctx context.Context, language string) (Locales, error) {
+   var result struct {
+      Locales Locales
+   }
+   err := c.graphql(
+      ctx,
+      backend_constants_fetcher_query,
+      map[string]string{"language": language},
+      &result,
+   )
+   if err != nil {
+      return nil, err
+   }
+   return result.Locales, nil
+}
+
+// Offers returns the offers for a title in the country of localeVar. If the
+// query itself fails, the error is a *GraphQLError
+func (c *Client) Offers(tag *HrefLangTag, localeVar *Locale) ([]Offer, error) {
+   return c.OffersContext(context.Background(), tag, localeVar)
+}
+
+func (c *Client) OffersContext(
+   ctx context.Context, tag *HrefLangTag, localeVar *Locale,
+) ([]Offer, error) {
+   var result struct {
+      Url struct {
+         Node struct {
+            Offers []Offer
+         }
+      }
+   }
+   err := c.graphql(
+      ctx,
+      get_url_title_details+offer_fields,
+      map[string]string{
+         "country":  localeVar.Country,
+         "fullPath": tag.Href,
+      },
+      &result,
+   )
+   if err != nil {
+      return nil, err
+   }
+   return result.Url.Node.Offers, nil
+}
@@ -0,0 +1,185 @@
+package justWatch
+
+import (
+   "cmp"
+   "context"
+   "encoding/csv"
+   "encoding/json"
+   "errors"
+   "io"
+   "slices"
+   "strings"
+)
+
+// Coverage is a provider by country matrix
+type Coverage struct {
+   Countries []string            `json:"countries"` // sorted
+   Providers []*ProviderCoverage `json:"providers"` // sorted by slug
+}
+
+// ProviderCoverage is one row of a Coverage
+type ProviderCoverage struct {
+   Slug      string   `json:"slug"`
+   ClearName string   `json:"clearName"`
+   Countries []string `json:"countries"` // sorted
+}
+
+// CountryError records a failure to fetch the providers of one country
+type CountryError struct {
+   Country string
+   Err     error
+}
+
+func (c *CountryError) Error() string {
+   return c.Country + ": " + c.Err.Error()
+}
+
+func (c *CountryError) Unwrap() error {
+   return c.Err
+}
+
+// FetchCoverage is a wrapper for DefaultClient.FetchCoverage
+func FetchCoverage(
+   ctx context.Context, locales Locales, opts *FetchOptions,
+) (*Coverage, error) {
+   return DefaultClient.FetchCoverage(ctx, locales, opts)
+}
+
+// FetchCoverage gets the provider catalog of every country in locales, once
+// per country. Providers without titles are left out. Workers, Limiter and
+// Region of opts are used. A failed country does not stop the others; every
+// failure is returned as a *CountryError joined into the error, and the
+// country is left out of the Coverage
+func (c *Client) FetchCoverage(
+   ctx context.Context, locales Locales, opts *FetchOptions,
+) (*Coverage, error) {
+   if opts == nil {
+      opts = &FetchOptions{}
+   }
+   var countries []string
+   for _, locale_data := range locales {
+      if opts.Region == nil || opts.Region(locale_data.Country) {
+         countries = append(countries, locale_data.Country)
+      }
+   }
+   slices.Sort(countries)
+   countries = slices.Compact(countries)
+   results := make([][]Package, len(countries))
+   errs := make([]error, len(countries))
+   each(opts.Workers, len(countries), func(i int) {
+      results[i], errs[i] = c.fetch_providers(ctx, countries[i], opts)
+   })
+   var coverage Coverage
+   rows := map[string]*ProviderCoverage{}
+   for i, country := range countries {
+      if errs[i] != nil {
+         continue
+      }
+      coverage.Countries = append(coverage.Countries, country)
+      for _, provider := range results[i] {
+         if !provider.HasTitles {
+            continue
+         }
+         row := rows[provider.Slug]
+         if row == nil {
+            row = &ProviderCoverage{
+               Slug: provider.Slug, ClearName: provider.ClearName,
+            }
+            rows[provider.Slug] = row
+            coverage.Providers = append(coverage.Providers, row)
+         }
+         if !slices.Contains(row.Countries, country) {
+            row.Countries = append(row.Countries, country)
+         }
+      }
+   }
+   slices.SortFunc(coverage.Providers, func(a, b *ProviderCoverage) int {
+      return cmp.Compare(a.Slug, b.Slug)
+   })
+   return &coverage, errors.Join(errs...)
+}
+
+func (c *Client) fetch_providers(
+   ctx context.Context, country string, opts *FetchOptions,
+) ([]Package, error) {
+   if opts.Limiter != nil {
+      err := opts.Limiter.Wait(ctx)
+      if err != nil {
+         return nil, &CountryError{country, err}
+      }
+   }
+   providers, err := c.ProvidersContext(ctx, country)
+   if err != nil {
+      return nil, &CountryError{country, err}
+   }
+   return providers, nil
+}
+
+// Provider returns the row of slug, or nil
+func (c *Coverage) Provider(slug string) *ProviderCoverage {
+   for _, row := range c.Providers {
+      if strings.EqualFold(row.Slug, slug) {
+         return row
+      }
+   }
+   return nil
+}
+
+// Where returns the countries that have every one of the providers, for
+// example "mubi" and "criterion-channel". An unknown slug matches nothing
+func (c *Coverage) Where(slugs ...string) []string {
+   countries := c.Countries
+   for _, slug := range slugs {
+      row := c.Provider(slug)
+      if row == nil {
+         return nil
+      }
+      countries = slices.DeleteFunc(slices.Clone(countries), func(v string) bool {
+         return !slices.Contains(row.Countries, v)
+      })
+   }
+   return countries
+}
+
+func ReadCoverage(r io.Reader) (*Coverage, error) {
+   var coverage Coverage
+   err := json.NewDecoder(r).Decode(&coverage)
+   if err != nil {
+      return nil, err
+   }
+   return &coverage, nil
+}
+
+func (c *Coverage) Json(w io.Writer) error {
+   encoder := json.NewEncoder(w)
+   encoder.SetIndent("", " ")
+   return encoder.Encode(c)
+}
+
+// Csv writes one row per provider and one column per country, with x where
+// the provider is available:
+//  slug,name,AD,AE,...
+//  mubi,MUBI,,x,...
+func (c *Coverage) Csv(w io.Writer) error {
+   writer := csv.NewWriter(w)
+   err := writer.Write(append([]string{"slug", "name"}, c.Countries...))
+   if err != nil {
+      return err
+   }
+   for _, row := range c.Providers {
+      record := []string{row.Slug, row.ClearName}
+      for _, country := range c.Countries {
+         if slices.Contains(row.Countries, country) {
+            record = append(record, "x")
+         } else {
+            record = append(record, "")
+         }
+      }
+      err = writer.Write(record)
+      if err != nil {
+         return err
+      }
+   }
+   writer.Flush()
+   return writer.Error()
+}
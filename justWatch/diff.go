@@ -0,0 +1,172 @@
+package justWatch
+
+import (
+   "cmp"
+   "encoding/json"
+   "io"
+   "maps"
+   "slices"
+   "strconv"
+   "strings"
+   "time"
+)
+
+// Snapshot is the offers of one run, saved to compare with a later run
+type Snapshot struct {
+   Time   time.Time
+   Offers []*EnrichedOffer
+}
+
+func WriteSnapshot(w io.Writer, offers []*EnrichedOffer) error {
+   encoder := json.NewEncoder(w)
+   encoder.SetIndent("", " ")
+   return encoder.Encode(Snapshot{time.Now().UTC(), offers})
+}
+
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+   var snapshot Snapshot
+   err := json.NewDecoder(r).Decode(&snapshot)
+   if err != nil {
+      return nil, err
+   }
+   return &snapshot, nil
+}
+
+// OfferKey identifies the offers of one service in one country
+type OfferKey struct {
+   Url     string // canonical, from the grouping key
+   Country string
+   Season  int
+   Episode int
+}
+
+func (o *OfferKey) String() string {
+   var data strings.Builder
+   data.WriteString(o.Country)
+   if o.Season >= 1 {
+      data.WriteString(" S")
+      data.WriteString(strconv.Itoa(o.Season))
+   }
+   if o.Episode >= 1 {
+      data.WriteString("E")
+      data.WriteString(strconv.Itoa(o.Episode))
+   }
+   data.WriteByte(' ')
+   data.WriteString(o.Url)
+   return data.String()
+}
+
+func (o *OfferKey) compare(p *OfferKey) int {
+   return cmp.Or(
+      cmp.Compare(o.Url, p.Url),
+      cmp.Compare(o.Country, p.Country),
+      o.Season-p.Season,
+      o.Episode-p.Episode,
+   )
+}
+
+// NewOfferKey returns the key Diff uses for enriched
+func NewOfferKey(enriched *EnrichedOffer) OfferKey {
+   return OfferKey{
+      Url:     getUrlGroupingKey(enriched.Offer.StandardWebUrl),
+      Country: enriched.Locale.Country,
+      Season:  enriched.Season,
+      Episode: enriched.Episode,
+   }
+}
+
+type OfferChange struct {
+   Key OfferKey
+   Old []*EnrichedOffer // empty if added
+   New []*EnrichedOffer // empty if removed
+}
+
+// MonetizationTypes returns the sorted unique monetization types of offers
+func MonetizationTypes(offers []*EnrichedOffer) []string {
+   var types []string
+   for _, offer := range offers {
+      types = append(types, offer.Offer.MonetizationType)
+   }
+   slices.Sort(types)
+   return slices.Compact(types)
+}
+
+type OfferDiff struct {
+   Added   []*OfferChange
+   Removed []*OfferChange
+   Changed []*OfferChange // monetization types differ
+}
+
+// Diff compares two runs. Offers are keyed by OfferKey, so a service that
+// changes tracking parameters is not reported
+func Diff(old, new []*EnrichedOffer) *OfferDiff {
+   changes := map[OfferKey]*OfferChange{}
+   change := func(enriched *EnrichedOffer) *OfferChange {
+      key := NewOfferKey(enriched)
+      if changes[key] == nil {
+         changes[key] = &OfferChange{Key: key}
+      }
+      return changes[key]
+   }
+   for _, enriched := range old {
+      value := change(enriched)
+      value.Old = append(value.Old, enriched)
+   }
+   for _, enriched := range new {
+      value := change(enriched)
+      value.New = append(value.New, enriched)
+   }
+   keys := slices.SortedFunc(maps.Keys(changes), func(a, b OfferKey) int {
+      return a.compare(&b)
+   })
+   var diff OfferDiff
+   for _, key := range keys {
+      value := changes[key]
+      switch {
+      case len(value.Old) == 0:
+         diff.Added = append(diff.Added, value)
+      case len(value.New) == 0:
+         diff.Removed = append(diff.Removed, value)
+      case !slices.Equal(
+         MonetizationTypes(value.Old), MonetizationTypes(value.New),
+      ):
+         diff.Changed = append(diff.Changed, value)
+      }
+   }
+   return &diff
+}
+
+// WriteTo writes a changelog, one line per change:
+//  + US https://www.netflix.com/title/60036164 FLATRATE
+//  - GB https://www.amazon.co.uk/gp/video/detail/B00FZ RENT,BUY
+//  ~ DE https://tv.apple.com/de/movie/umc.cmc.3s4 RENT -> FLATRATE
+func (o *OfferDiff) WriteTo(w io.Writer) (int64, error) {
+   var data strings.Builder
+   line := func(sign string, change *OfferChange, types ...[]string) {
+      data.WriteString(sign)
+      data.WriteByte(' ')
+      data.WriteString(change.Key.String())
+      for i, value := range types {
+         if i >= 1 {
+            data.WriteString(" ->")
+         }
+         data.WriteByte(' ')
+         data.WriteString(strings.Join(value, ","))
+      }
+      data.WriteByte('\n')
+   }
+   for _, change := range o.Added {
+      line("+", change, MonetizationTypes(change.New))
+   }
+   for _, change := range o.Removed {
+      line("-", change, MonetizationTypes(change.Old))
+   }
+   for _, change := range o.Changed {
+      line(
+         "~", change,
+         MonetizationTypes(change.Old), MonetizationTypes(change.New),
+      )
+   }
+   n, err := io.WriteString(w, data.String())
+   return int64(n), err
+}
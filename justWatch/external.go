@@ -0,0 +1,92 @@
+package justWatch
+
+import (
+   "context"
+   "errors"
+   "strings"
+)
+
+var ErrNotFound = errors.New("title not found")
+
+// ExternalId identifies a title outside JustWatch
+type ExternalId struct {
+   Source string // imdb, tmdb_movie or tmdb_show
+   Id     string // tt0099685 or 769
+   Title  string // Goodfellas, optional, searched if Id is not found
+}
+
+// ParseExternalId accepts
+//  tt0099685
+//  tmdb:movie/769
+//  tmdb:tv/1920
+func ParseExternalId(data string) (*ExternalId, error) {
+   if strings.HasPrefix(data, "tt") {
+      return &ExternalId{Source: "imdb", Id: data}, nil
+   }
+   if rest, ok := strings.CutPrefix(data, "tmdb:"); ok {
+      kind, id, ok := strings.Cut(rest, "/")
+      if ok && id != "" {
+         switch kind {
+         case "movie":
+            return &ExternalId{Source: "tmdb_movie", Id: id}, nil
+         case "tv":
+            return &ExternalId{Source: "tmdb_show", Id: id}, nil
+         }
+      }
+   }
+   return nil, errors.New("invalid external id: " + data)
+}
+
+func (e *ExternalId) match(title *TitleDetails) bool {
+   switch e.Source {
+   case "imdb":
+      return title.Content.ExternalIds.ImdbId == e.Id
+   case "tmdb_movie":
+      return title.ObjectType == "MOVIE" &&
+         title.Content.ExternalIds.TmdbId == e.Id
+   case "tmdb_show":
+      return title.ObjectType == "SHOW" &&
+         title.Content.ExternalIds.TmdbId == e.Id
+   }
+   return false
+}
+
+// Resolve is a wrapper for DefaultClient.Resolve
+func Resolve(id *ExternalId, country string) (*TitleDetails, *Content, error) {
+   return DefaultClient.Resolve(id, country)
+}
+
+// Resolve finds the title with id in country, for example "US", and returns
+// it with its Content. JustWatch has no lookup by external id, so this
+// searches for id.Id, then for id.Title if it is set, and keeps the first
+// result whose external ids match. ErrNotFound is returned if none match
+func (c *Client) Resolve(
+   id *ExternalId, country string,
+) (*TitleDetails, *Content, error) {
+   return c.ResolveContext(context.Background(), id, country)
+}
+
+func (c *Client) ResolveContext(
+   ctx context.Context, id *ExternalId, country string,
+) (*TitleDetails, *Content, error) {
+   queries := []string{id.Id}
+   if id.Title != "" {
+      queries = append(queries, id.Title)
+   }
+   for _, query := range queries {
+      titles, err := c.SearchContext(ctx, query, country)
+      if err != nil {
+         return nil, nil, err
+      }
+      for _, title := range titles {
+         if id.match(&title) {
+            content, err := c.ContentContext(ctx, title.Content.FullPath)
+            if err != nil {
+               return nil, nil, err
+            }
+            return &title, content, nil
+         }
+      }
+   }
+   return nil, nil, ErrNotFound
+}
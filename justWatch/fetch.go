@@ -0,0 +1,166 @@
+package justWatch
+
+import (
+   "context"
+   "errors"
+   "sync"
+   "time"
+)
+
+// Limiter is a token bucket. It holds up to Burst tokens and gains one token
+// every Interval. The zero value does not limit anything
+type Limiter struct {
+   Interval time.Duration
+   Burst    int // <= 0 for 1
+   mu       sync.Mutex
+   tokens   float64
+   last     time.Time
+}
+
+// Wait blocks until a token is available or ctx is done
+func (l *Limiter) Wait(ctx context.Context) error {
+   if l.Interval <= 0 {
+      return ctx.Err()
+   }
+   burst := float64(max(l.Burst, 1))
+   for {
+      l.mu.Lock()
+      now := time.Now()
+      if l.last.IsZero() {
+         l.tokens = burst
+      } else {
+         l.tokens += float64(now.Sub(l.last)) / float64(l.Interval)
+         l.tokens = min(l.tokens, burst)
+      }
+      l.last = now
+      if l.tokens >= 1 {
+         l.tokens--
+         l.mu.Unlock()
+         return nil
+      }
+      wait := time.Duration((1 - l.tokens) * float64(l.Interval))
+      l.mu.Unlock()
+      timer := time.NewTimer(wait)
+      select {
+      case <-ctx.Done():
+         timer.Stop()
+         return ctx.Err()
+      case <-timer.C:
+      }
+   }
+}
+
+type FetchOptions struct {
+   Workers int       // <= 0 for 1
+   Limiter *Limiter  // nil for no limit
+   Seasons bool      // also fetch season and episode offers of shows
+   Region  RegionSet // nil for every country
+}
+
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// LocaleError records a failure to fetch the offers for one href lang tag
+type LocaleError struct {
+   Tag HrefLangTag
+   Err error
+}
+
+func (l *LocaleError) Error() string {
+   return l.Tag.Locale + " " + l.Tag.Href + ": " + l.Err.Error()
+}
+
+func (l *LocaleError) Unwrap() error {
+   return l.Err
+}
+
+// FetchOffers is a wrapper for DefaultClient.FetchOffers
+func FetchOffers(
+   ctx context.Context, content *Content, locales Locales, opts *FetchOptions,
+) ([]*EnrichedOffer, error) {
+   return DefaultClient.FetchOffers(ctx, content, locales, opts)
+}
+
+// FetchOffers gets the offers for every href lang tag of content. Tags
+// missing from locales are looked up with Derive. Offers are
+// returned in href lang tag order, then in the order JustWatch returned them.
+// A failed tag does not stop the others; every failure is returned as a
+// *LocaleError joined into the error
+func (c *Client) FetchOffers(
+   ctx context.Context, content *Content, locales Locales, opts *FetchOptions,
+) ([]*EnrichedOffer, error) {
+   if opts == nil {
+      opts = &FetchOptions{}
+   }
+   tags := content.HrefLangTags
+   results := make([][]*EnrichedOffer, len(tags))
+   errs := make([]error, len(tags))
+   each(opts.Workers, len(tags), func(i int) {
+      results[i], errs[i] = c.fetch_offers(ctx, &tags[i], locales, opts)
+   })
+   var offers []*EnrichedOffer
+   for _, result := range results {
+      offers = append(offers, result...)
+   }
+   return offers, errors.Join(errs...)
+}
+
+// each calls fn with 0 to n-1 from workers goroutines, and waits
+func each(workers, n int, fn func(int)) {
+   jobs := make(chan int)
+   var group sync.WaitGroup
+   for range max(workers, 1) {
+      group.Go(func() {
+         for i := range jobs {
+            fn(i)
+         }
+      })
+   }
+   for i := range n {
+      jobs <- i
+   }
+   close(jobs)
+   group.Wait()
+}
+
+func (c *Client) fetch_offers(
+   ctx context.Context, tag *HrefLangTag, locales Locales, opts *FetchOptions,
+) ([]*EnrichedOffer, error) {
+   locale, ok := locales.Lookup(tag)
+   if !ok {
+      return nil, &LocaleError{*tag, ErrUnknownLocale}
+   }
+   if opts.Region != nil && !opts.Region(locale.Country) {
+      return nil, nil
+   }
+   if opts.Limiter != nil {
+      err := opts.Limiter.Wait(ctx)
+      if err != nil {
+         return nil, &LocaleError{*tag, err}
+      }
+   }
+   offers, err := c.OffersContext(ctx, tag, locale)
+   if err != nil {
+      return nil, &LocaleError{*tag, err}
+   }
+   enriched := make([]*EnrichedOffer, len(offers))
+   for i := range offers {
+      enriched[i] = &EnrichedOffer{Locale: locale, Offer: &offers[i]}
+   }
+   if opts.Seasons {
+      if opts.Limiter != nil {
+         err = opts.Limiter.Wait(ctx)
+         if err != nil {
+            return nil, &LocaleError{*tag, err}
+         }
+      }
+      seasons, err := c.SeasonsContext(ctx, tag, locale)
+      if err != nil {
+         return nil, &LocaleError{*tag, err}
+      }
+      enriched = append(enriched, SeasonOffers(locale, seasons)...)
+   }
+   for _, value := range enriched {
+      value.Href = tag.Href
+   }
+   return enriched, nil
+}
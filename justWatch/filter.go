@@ -0,0 +1,371 @@
+package justWatch
+
+import (
+   "errors"
+   "fmt"
+   "net/url"
+   "slices"
+   "strconv"
+   "strings"
+   "unicode"
+)
+
+// Predicate reports whether to keep an offer
+type Predicate func(*EnrichedOffer) bool
+
+// Filter returns the offers p keeps
+func Filter(offers []*EnrichedOffer, p Predicate) []*EnrichedOffer {
+   var filteredOffers []*EnrichedOffer
+   for _, offer := range offers {
+      if p(offer) {
+         filteredOffers = append(filteredOffers, offer)
+      }
+   }
+   return filteredOffers
+}
+
+func And(predicates ...Predicate) Predicate {
+   return func(offer *EnrichedOffer) bool {
+      for _, p := range predicates {
+         if !p(offer) {
+            return false
+         }
+      }
+      return true
+   }
+}
+
+func Or(predicates ...Predicate) Predicate {
+   return func(offer *EnrichedOffer) bool {
+      for _, p := range predicates {
+         if p(offer) {
+            return true
+         }
+      }
+      return false
+   }
+}
+
+func Not(p Predicate) Predicate {
+   return func(offer *EnrichedOffer) bool {
+      return !p(offer)
+   }
+}
+
+func field_in(field func(*EnrichedOffer) string, values []string) Predicate {
+   return func(offer *EnrichedOffer) bool {
+      value := field(offer)
+      return slices.ContainsFunc(values, func(v string) bool {
+         return strings.EqualFold(v, value)
+      })
+   }
+}
+
+// Country keeps offers in any of the countries, such as US
+func Country(countries ...string) Predicate {
+   return field_in(ByCountry, countries)
+}
+
+// InContinent keeps offers in any of the continents, such as EU
+func InContinent(continents ...string) Predicate {
+   return field_in(func(offer *EnrichedOffer) string {
+      return Continent(offer.Locale.Country)
+   }, continents)
+}
+
+// Monetization keeps offers with any of the types, such as FLATRATE
+func Monetization(types ...string) Predicate {
+   return field_in(ByMonetization, types)
+}
+
+// Provider keeps offers from any of the packages, by technical name such as
+// netflix or by clear name such as Netflix
+func Provider(names ...string) Predicate {
+   return Or(
+      field_in(func(offer *EnrichedOffer) string {
+         return offer.Offer.Package.TechnicalName
+      }, names),
+      field_in(func(offer *EnrichedOffer) string {
+         return offer.Offer.Package.ClearName
+      }, names),
+   )
+}
+
+// Host keeps offers whose address is on any of the hosts. www. is ignored
+// on both sides
+func Host(hosts ...string) Predicate {
+   bare := make([]string, len(hosts))
+   for i, host := range hosts {
+      bare[i] = strings.TrimPrefix(strings.ToLower(host), "www.")
+   }
+   return field_in(func(offer *EnrichedOffer) string {
+      address, err := url.Parse(offer.Offer.StandardWebUrl)
+      if err != nil {
+         return ""
+      }
+      return strings.TrimPrefix(address.Host, "www.")
+   }, bare)
+}
+
+// Presentation keeps offers with any of the types, such as HD
+func Presentation(types ...string) Predicate {
+   return field_in(func(offer *EnrichedOffer) string {
+      return offer.Offer.PresentationType
+   }, types)
+}
+
+// Count compares ElementCount with n. op is one of = != < <= > >=
+func Count(op string, n int) (Predicate, error) {
+   compare, ok := map[string]func(a, b int) bool{
+      "=":  func(a, b int) bool { return a == b },
+      "!=": func(a, b int) bool { return a != b },
+      "<":  func(a, b int) bool { return a < b },
+      "<=": func(a, b int) bool { return a <= b },
+      ">":  func(a, b int) bool { return a > b },
+      ">=": func(a, b int) bool { return a >= b },
+   }[op]
+   if !ok {
+      return nil, errors.New("unknown operator: " + op)
+   }
+   return func(offer *EnrichedOffer) bool {
+      return compare(offer.Offer.ElementCount, n)
+   }, nil
+}
+
+var filter_fields = map[string]func(...string) Predicate{
+   "continent":    InContinent,
+   "country":      Country,
+   "host":         Host,
+   "monetization": Monetization,
+   "presentation": Presentation,
+   "provider":     Provider,
+   "region":       InRegion,
+}
+
+// ParseFilter parses an expression such as
+//  country in (US,GB) and not monetization=RENT
+//  continent=EU or (host=netflix.com and count>=2)
+// Fields are continent, country, host, monetization, presentation, provider
+// and region, compared with = != or in, and count, compared with = != < <=
+// > >=. Region values are as for LookupRegionSet. Keywords are and, or, not,
+// in. Values can be quoted with "
+func ParseFilter(expression string) (Predicate, error) {
+   tokens, err := lex_filter(expression)
+   if err != nil {
+      return nil, err
+   }
+   parser := filter_parser{tokens: tokens}
+   p, err := parser.or()
+   if err != nil {
+      return nil, err
+   }
+   if parser.pos < len(tokens) {
+      return nil, parser.unexpected()
+   }
+   return p, nil
+}
+
+func lex_filter(expression string) ([]string, error) {
+   var tokens []string
+   runes := []rune(expression)
+   for i := 0; i < len(runes); {
+      r := runes[i]
+      switch {
+      case unicode.IsSpace(r):
+         i++
+      case strings.ContainsRune("(),", r):
+         tokens = append(tokens, string(r))
+         i++
+      case strings.ContainsRune("=!<>", r):
+         if i+1 < len(runes) && runes[i+1] == '=' {
+            tokens = append(tokens, string(runes[i:i+2]))
+            i += 2
+         } else if r == '!' {
+            return nil, fmt.Errorf("filter: expected != at %v", i)
+         } else {
+            tokens = append(tokens, string(r))
+            i++
+         }
+      case r == '"':
+         end := slices.Index(runes[i+1:], '"')
+         if end == -1 {
+            return nil, fmt.Errorf("filter: unterminated string at %v", i)
+         }
+         // keep the quote so a value is never taken as a keyword
+         tokens = append(tokens, string(runes[i:i+end+1]))
+         i += end + 2
+      default:
+         start := i
+         for i < len(runes) {
+            r = runes[i]
+            if unicode.IsSpace(r) || strings.ContainsRune("(),=!<>\"", r) {
+               break
+            }
+            i++
+         }
+         tokens = append(tokens, string(runes[start:i]))
+      }
+   }
+   return tokens, nil
+}
+
+type filter_parser struct {
+   tokens []string
+   pos    int
+}
+
+func (f *filter_parser) peek() string {
+   if f.pos < len(f.tokens) {
+      return f.tokens[f.pos]
+   }
+   return ""
+}
+
+func (f *filter_parser) keyword(word string) bool {
+   if strings.EqualFold(f.peek(), word) {
+      f.pos++
+      return true
+   }
+   return false
+}
+
+func (f *filter_parser) unexpected() error {
+   if f.pos >= len(f.tokens) {
+      return errors.New("filter: unexpected end")
+   }
+   return fmt.Errorf("filter: unexpected %q", f.tokens[f.pos])
+}
+
+func (f *filter_parser) or() (Predicate, error) {
+   p, err := f.and()
+   if err != nil {
+      return nil, err
+   }
+   predicates := []Predicate{p}
+   for f.keyword("or") {
+      p, err = f.and()
+      if err != nil {
+         return nil, err
+      }
+      predicates = append(predicates, p)
+   }
+   if len(predicates) == 1 {
+      return p, nil
+   }
+   return Or(predicates...), nil
+}
+
+func (f *filter_parser) and() (Predicate, error) {
+   p, err := f.unary()
+   if err != nil {
+      return nil, err
+   }
+   predicates := []Predicate{p}
+   for f.keyword("and") {
+      p, err = f.unary()
+      if err != nil {
+         return nil, err
+      }
+      predicates = append(predicates, p)
+   }
+   if len(predicates) == 1 {
+      return p, nil
+   }
+   return And(predicates...), nil
+}
+
+func (f *filter_parser) unary() (Predicate, error) {
+   if f.keyword("not") {
+      p, err := f.unary()
+      if err != nil {
+         return nil, err
+      }
+      return Not(p), nil
+   }
+   if f.keyword("(") {
+      p, err := f.or()
+      if err != nil {
+         return nil, err
+      }
+      if !f.keyword(")") {
+         return nil, f.unexpected()
+      }
+      return p, nil
+   }
+   return f.term()
+}
+
+func (f *filter_parser) value() (string, error) {
+   token := f.peek()
+   if token == "" || strings.ContainsAny(token[:1], "(),=!<>") {
+      return "", f.unexpected()
+   }
+   f.pos++
+   return strings.TrimPrefix(token, `"`), nil
+}
+
+func (f *filter_parser) term() (Predicate, error) {
+   field := strings.ToLower(f.peek())
+   if field == "count" {
+      f.pos++
+      op := f.peek()
+      f.pos++
+      value, err := f.value()
+      if err != nil {
+         return nil, err
+      }
+      n, err := strconv.Atoi(value)
+      if err != nil {
+         return nil, fmt.Errorf("filter: count %w", err)
+      }
+      return Count(op, n)
+   }
+   predicate, ok := filter_fields[field]
+   if !ok {
+      return nil, fmt.Errorf("filter: unknown field %q", f.peek())
+   }
+   f.pos++
+   var (
+      values []string
+      negate bool
+   )
+   switch {
+   case f.keyword("="), f.keyword("!="):
+      negate = f.tokens[f.pos-1] == "!="
+      value, err := f.value()
+      if err != nil {
+         return nil, err
+      }
+      values = append(values, value)
+   case f.keyword("in"):
+      if !f.keyword("(") {
+         return nil, f.unexpected()
+      }
+      for {
+         value, err := f.value()
+         if err != nil {
+            return nil, err
+         }
+         values = append(values, value)
+         if f.keyword(")") {
+            break
+         }
+         if !f.keyword(",") {
+            return nil, f.unexpected()
+         }
+      }
+   default:
+      return nil, f.unexpected()
+   }
+   if field == "region" {
+      for _, value := range values {
+         if _, err := LookupRegionSet(value); err != nil {
+            return nil, fmt.Errorf("filter: %w", err)
+         }
+      }
+   }
+   if negate {
+      return Not(predicate(values...)), nil
+   }
+   return predicate(values...), nil
+}
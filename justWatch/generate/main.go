@@ -0,0 +1,148 @@
+// Generate writes a Locales table from justWatch.Hello, or with -t a
+// Translations table from justWatch.FetchTranslations. With -check it writes
+// nothing, and fails if the table on disk is stale
+package main
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "bytes"
+   "context"
+   "errors"
+   "flag"
+   "log"
+   "maps"
+   "os"
+   "os/signal"
+   "slices"
+   "strconv"
+   "strings"
+   "time"
+)
+
+func main() {
+   log.SetFlags(log.Ltime)
+   ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+   defer stop()
+   err := new(generator).do(ctx)
+   if err != nil {
+      log.Fatal(err)
+   }
+}
+
+type generator struct {
+   language string
+   variable string
+   output       string
+   check        bool
+   translations bool
+}
+
+func (g *generator) do(ctx context.Context) error {
+   flag.StringVar(&g.language, "l", "en-US", "language")
+   flag.StringVar(&g.variable, "v", "EnUs", "variable")
+   flag.StringVar(&g.output, "o", "en_us.go", "output")
+   flag.BoolVar(&g.check, "check", false, "check")
+   flag.BoolVar(&g.translations, "t", false, "translations")
+   flag.Parse()
+   var data []byte
+   if g.translations {
+      translations, err := justWatch.FetchTranslations(ctx)
+      if err != nil {
+         return err
+      }
+      data = g.format_translations(translations, time.Now())
+   } else {
+      locales, err := justWatch.HelloContext(ctx, g.language)
+      if err != nil {
+         return err
+      }
+      data = g.format(locales, time.Now())
+   }
+   if g.check {
+      return g.do_check(data)
+   }
+   log.Println("WriteFile", g.output)
+   return os.WriteFile(g.output, data, os.ModePerm)
+}
+
+func (g *generator) header(data *bytes.Buffer, args []string, now time.Time) {
+   data.WriteString("// Code generated by ")
+   data.WriteString(strconv.Quote(strings.Join(append(
+      append([]string{"go run ./generate"}, args...),
+      "-v", g.variable,
+      "-o", g.output,
+   ), " ")))
+   data.WriteString("; DO NOT EDIT.\n\n")
+   data.WriteString("package justWatch\n\n")
+   data.WriteString("// ")
+   data.WriteString(now.Format(time.DateOnly))
+   data.WriteString("\nvar ")
+   data.WriteString(g.variable)
+}
+
+func (g *generator) format(locales justWatch.Locales, now time.Time) []byte {
+   var data bytes.Buffer
+   g.header(&data, []string{"-l", g.language}, now)
+   data.WriteString(" = Locales{\n")
+   for _, locale := range locales {
+      data.WriteString("   {FullLocale: ")
+      data.WriteString(strconv.Quote(locale.FullLocale))
+      data.WriteString(", Country: ")
+      data.WriteString(strconv.Quote(locale.Country))
+      data.WriteString(", CountryName: ")
+      data.WriteString(strconv.Quote(locale.CountryName))
+      data.WriteString("},\n")
+   }
+   data.WriteString("}\n")
+   return data.Bytes()
+}
+
+// sorted by prefix
+func (g *generator) format_translations(
+   translations justWatch.Translations, now time.Time,
+) []byte {
+   var data bytes.Buffer
+   g.header(&data, []string{"-t"}, now)
+   data.WriteString(" = Translations{\n")
+   for _, prefix := range slices.Sorted(maps.Keys(translations)) {
+      value := translations[prefix]
+      data.WriteString("   ")
+      data.WriteString(strconv.Quote(prefix))
+      data.WriteString(": {Provider: ")
+      data.WriteString(strconv.Quote(value.Provider))
+      data.WriteString(", Movie: ")
+      data.WriteString(strconv.Quote(value.Movie))
+      data.WriteString(", Show: ")
+      data.WriteString(strconv.Quote(value.Show))
+      data.WriteString("},\n")
+   }
+   data.WriteString("}\n")
+   return data.Bytes()
+}
+
+// the date line changes every run, so it is left out
+func (g *generator) do_check(data []byte) error {
+   current, err := os.ReadFile(g.output)
+   if err != nil {
+      return err
+   }
+   if !bytes.Equal(without_date(current), without_date(data)) {
+      return errors.New(g.output + " is stale, run go generate")
+   }
+   log.Println(g.output, "is current")
+   return nil
+}
+
+func without_date(data []byte) []byte {
+   var lines [][]byte
+   for line := range bytes.Lines(data) {
+      date, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("// "))
+      if ok {
+         if _, err := time.Parse(time.DateOnly, string(date)); err == nil {
+            continue
+         }
+      }
+      lines = append(lines, line)
+   }
+   return bytes.Join(lines, nil)
+}
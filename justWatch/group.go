@@ -0,0 +1,180 @@
+package justWatch
+
+import (
+   "cmp"
+   "maps"
+   "net/url"
+   "slices"
+   "strconv"
+)
+
+// KeyFunc returns the group of an offer
+type KeyFunc func(*EnrichedOffer) string
+
+// GroupOrder compares two groups
+type GroupOrder func(a, b *OfferGroup) int
+
+// OfferOrder compares two offers in the same group
+type OfferOrder func(a, b *EnrichedOffer) int
+
+type OfferGroup struct {
+   Key    string
+   Offers []*EnrichedOffer
+}
+
+// Grouping groups offers by Key, orders the groups by Groups and the offers
+// in each group by Offers. A nil Groups sorts the keys, and a nil Offers keeps
+// the input order
+type Grouping struct {
+   Key    KeyFunc
+   Groups GroupOrder
+   Offers OfferOrder
+}
+
+func (g *Grouping) Group(offers []*EnrichedOffer) ([]string, map[string][]*EnrichedOffer) {
+   groupedOffers := make(map[string][]*EnrichedOffer)
+   for _, offer := range offers {
+      key := g.Key(offer)
+      groupedOffers[key] = append(groupedOffers[key], offer)
+   }
+   if g.Offers != nil {
+      for _, offerGroup := range groupedOffers {
+         slices.SortStableFunc(offerGroup, g.Offers)
+      }
+   }
+   keys := slices.Sorted(maps.Keys(groupedOffers))
+   if g.Groups != nil {
+      slices.SortStableFunc(keys, func(a, b string) int {
+         return g.Groups(
+            &OfferGroup{a, groupedOffers[a]}, &OfferGroup{b, groupedOffers[b]},
+         )
+      })
+   }
+   return keys, groupedOffers
+}
+
+func ByUrl(offer *EnrichedOffer) string {
+   return getUrlGroupingKey(offer.Offer.StandardWebUrl)
+}
+
+func ByCountry(offer *EnrichedOffer) string {
+   return offer.Locale.Country
+}
+
+func ByMonetization(offer *EnrichedOffer) string {
+   return offer.Offer.MonetizationType
+}
+
+// ByProvider uses the package name, or the host if the offer has none
+func ByProvider(offer *EnrichedOffer) string {
+   if offer.Offer.Package.ClearName != "" {
+      return offer.Offer.Package.ClearName
+   }
+   address, err := url.Parse(offer.Offer.StandardWebUrl)
+   if err != nil {
+      return ""
+   }
+   return address.Host
+}
+
+// BySeason uses "title" for offers of the whole title, else "season 1" and
+// so on. Episodes are in the group of their season
+func BySeason(offer *EnrichedOffer) string {
+   if offer.Season >= 1 {
+      return "season " + strconv.Itoa(offer.Season)
+   }
+   return "title"
+}
+
+var KeyFuncs = map[string]KeyFunc{
+   "country":      ByCountry,
+   "monetization": ByMonetization,
+   "provider":     ByProvider,
+   "season":       BySeason,
+   "url":          ByUrl,
+}
+
+// KeyLength puts shorter keys first
+func KeyLength(a, b *OfferGroup) int {
+   return cmp.Compare(len(a.Key), len(b.Key))
+}
+
+func KeyAlpha(a, b *OfferGroup) int {
+   return cmp.Compare(a.Key, b.Key)
+}
+
+// GroupSize puts larger groups first
+func GroupSize(a, b *OfferGroup) int {
+   return cmp.Compare(len(b.Offers), len(a.Offers))
+}
+
+// SeasonGroups puts the whole title first, then seasons in number order. It
+// is for groups from BySeason
+func SeasonGroups(a, b *OfferGroup) int {
+   return a.Offers[0].Season - b.Offers[0].Season
+}
+
+var GroupOrders = map[string]GroupOrder{
+   "alpha":  KeyAlpha,
+   "length": KeyLength,
+   "season": SeasonGroups,
+   "size":   GroupSize,
+}
+
+func CountryOrder(a, b *EnrichedOffer) int {
+   return cmp.Compare(a.Locale.Country, b.Locale.Country)
+}
+
+// SeasonOrder puts offers for the whole title first, then by season and
+// episode
+func SeasonOrder(a, b *EnrichedOffer) int {
+   return cmp.Or(a.Season-b.Season, a.Episode-b.Episode)
+}
+
+func MonetizationOrder(a, b *EnrichedOffer) int {
+   return cmp.Compare(a.Offer.MonetizationType, b.Offer.MonetizationType)
+}
+
+func ProviderOrder(a, b *EnrichedOffer) int {
+   return cmp.Compare(ByProvider(a), ByProvider(b))
+}
+
+// PriceOrder puts cheaper offers first. Prices in different currencies are
+// compared as numbers
+func PriceOrder(a, b *EnrichedOffer) int {
+   return cmp.Compare(a.Offer.RetailPriceValue, b.Offer.RetailPriceValue)
+}
+
+// Then compares by each order in turn
+func Then(orders ...OfferOrder) OfferOrder {
+   return func(a, b *EnrichedOffer) int {
+      for _, order := range orders {
+         if c := order(a, b); c != 0 {
+            return c
+         }
+      }
+      return 0
+   }
+}
+
+var OfferOrders = map[string]OfferOrder{
+   "country":      Then(CountryOrder, SeasonOrder),
+   "monetization": Then(MonetizationOrder, CountryOrder, SeasonOrder),
+   "price":        Then(PriceOrder, CountryOrder, SeasonOrder),
+   "provider":     Then(ProviderOrder, CountryOrder, SeasonOrder),
+   "season":       Then(SeasonOrder, CountryOrder),
+}
+
+// SeasonGrouping is what GroupBySeason uses, with string keys
+var SeasonGrouping = Grouping{
+   Key:    BySeason,
+   Groups: SeasonGroups,
+   Offers: Then(SeasonOrder, CountryOrder),
+}
+
+// UrlGrouping is what GroupAndSortByUrl uses
+var UrlGrouping = Grouping{
+   Key:    ByUrl,
+   Groups: KeyLength,
+   Offers: Then(CountryOrder, SeasonOrder),
+}
@@ -1,12 +1,23 @@
 package justWatch
 
 import (
+   "cmp"
+   "context"
+   "encoding/json"
+   "errors"
    "fmt"
+   "net/http"
+   "net/http/httptest"
+   "os"
+   "path/filepath"
+   "slices"
+   "strings"
    "testing"
+   "time"
 )
 
 func Test(t *testing.T) {
-   locales_data, err := NewLocales("en-US")
+   locales_data, err := Hello("en-US")
    if err != nil {
       t.Fatal(err)
    }
@@ -19,3 +30,886 @@ func Test(t *testing.T) {
    }
    fmt.Println(locale_data)
 }
+
+func TestFetchOffers(t *testing.T) {
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         var body struct {
+            Variables map[string]string
+         }
+         json.NewDecoder(req.Body).Decode(&body)
+         fmt.Fprintf(w,
+            `{"data":{"url":{"node":{"offers":[{"standardWebURL":%q}]}}}}`,
+            body.Variables["country"],
+         )
+      },
+   ))
+   defer server.Close()
+   client := Client{BaseUrl: server.URL}
+   content := Content{HrefLangTags: []HrefLangTag{
+      {Locale: "en_US"}, {Locale: "xx"}, {Locale: "de_DE"},
+      {Locale: "en_XX"}, {Locale: "en_GB"},
+   }}
+   offers, err := client.FetchOffers(
+      context.Background(), &content, EnUs, &FetchOptions{Workers: 3},
+   )
+   if !errors.Is(err, ErrUnknownLocale) {
+      t.Fatal(err)
+   }
+   var got []string
+   for _, offer := range offers {
+      got = append(got, offer.Offer.StandardWebUrl)
+   }
+   // xx is not a locale, en_XX is missing from EnUs and falls back to Derive
+   if fmt.Sprint(got) != "[US DE XX GB]" {
+      t.Fatal(got)
+   }
+   if derived := offers[2].Locale; derived.Country != "XX" ||
+      derived.FullLocale != "en_XX" {
+      t.Fatal(derived)
+   }
+}
+
+func TestGraphQLError(t *testing.T) {
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         fmt.Fprint(w, `{
+            "errors": [{
+               "message": "country not found",
+               "path": ["url", "node", "offers"],
+               "extensions": {"code": "BAD_USER_INPUT"}
+            }],
+            "data": {"url": {"node": {"offers": null}}}
+         }`)
+      },
+   ))
+   defer server.Close()
+   client := Client{BaseUrl: server.URL}
+   _, err := client.Offers(&HrefLangTag{}, &Locale{})
+   var graphql_err *GraphQLError
+   if !errors.As(err, &graphql_err) {
+      t.Fatal(err)
+   }
+   if graphql_err.Extensions["code"] != "BAD_USER_INPUT" {
+      t.Fatal(graphql_err)
+   }
+   fmt.Println(err)
+}
+
+func TestRetry(t *testing.T) {
+   var attempts int
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         attempts++
+         if attempts <= 2 {
+            http.Error(w, "slow down", http.StatusTooManyRequests)
+            return
+         }
+         fmt.Fprint(w, `{"href_lang_tags":[{"locale":"en_US"}]}`)
+      },
+   ))
+   defer server.Close()
+   var retries int
+   client := Client{
+      BaseUrl: server.URL,
+      Retry: &RetryPolicy{
+         MaxAttempts: 3,
+         BaseDelay:   time.Millisecond,
+         OnRetry: func(attempt int, delay time.Duration, err error) {
+            if !errors.Is(err, ErrRateLimited) {
+               t.Fatal(err)
+            }
+            retries++
+         },
+      },
+   }
+   content, err := client.Content("/us/movie/goodfellas")
+   if err != nil {
+      t.Fatal(err)
+   }
+   if retries != 2 || len(content.HrefLangTags) != 1 {
+      t.Fatal(retries, content)
+   }
+   client.Retry.MaxAttempts = 2
+   attempts = 0
+   _, err = client.Content("/us/movie/goodfellas")
+   if !errors.Is(err, ErrRateLimited) {
+      t.Fatal(err)
+   }
+}
+
+func TestRetryDelay(t *testing.T) {
+   policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+   err := &StatusError{StatusCode: 429, RetryAfter: time.Hour}
+   if delay := policy.delay(1, err); delay != time.Minute {
+      t.Fatal(delay)
+   }
+   if delay := policy.delay(99, nil); delay > time.Minute {
+      t.Fatal(delay)
+   }
+   policy.MaxDelay = 0
+   for attempt := 1; attempt <= 99; attempt++ {
+      if delay := policy.delay(attempt, nil); delay < time.Second/2 {
+         t.Fatal(attempt, delay)
+      }
+   }
+}
+
+func TestCache(t *testing.T) {
+   var requests int
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         requests++
+         fmt.Fprint(w, `{"data":{"url":{"node":{"offers":[{}]}}}}`)
+      },
+   ))
+   defer server.Close()
+   client := Client{
+      BaseUrl: server.URL, Cache: &FileCache{t.TempDir()},
+   }
+   for _, country := range []string{"US", "US", "GB"} {
+      _, err := client.Offers(&HrefLangTag{}, &Locale{Country: country})
+      if err != nil {
+         t.Fatal(err)
+      }
+   }
+   if requests != 2 {
+      t.Fatal(requests)
+   }
+   client.Refresh = true
+   _, err := client.Offers(&HrefLangTag{}, &Locale{Country: "US"})
+   if err != nil {
+      t.Fatal(err)
+   }
+   if requests != 3 {
+      t.Fatal(requests)
+   }
+   // another server does not get the responses of the first
+   other := httptest.NewServer(server.Config.Handler)
+   defer other.Close()
+   other_client := Client{BaseUrl: other.URL, Cache: client.Cache}
+   _, err = other_client.Offers(&HrefLangTag{}, &Locale{Country: "US"})
+   if err != nil {
+      t.Fatal(err)
+   }
+   if requests != 4 {
+      t.Fatal(requests)
+   }
+   // a cache that cannot be written does not fail the response
+   file := filepath.Join(t.TempDir(), "file")
+   err = os.WriteFile(file, nil, os.ModePerm)
+   if err != nil {
+      t.Fatal(err)
+   }
+   other_client.Cache = &FileCache{file}
+   _, err = other_client.Offers(&HrefLangTag{}, &Locale{Country: "US"})
+   if err != nil {
+      t.Fatal(err)
+   }
+}
+
+func TestDiff(t *testing.T) {
+   us := &Locale{Country: "US"}
+   gb := &Locale{Country: "GB"}
+   de := &Locale{Country: "DE"}
+   offer := func(locale_data *Locale, address, monetization string) *EnrichedOffer {
+      return &EnrichedOffer{
+         Locale: locale_data,
+         Offer: &Offer{
+            StandardWebUrl: address, MonetizationType: monetization,
+         },
+      }
+   }
+   old := []*EnrichedOffer{
+      offer(us, "https://a.com/1?utm_source=justwatch", "RENT"),
+      offer(us, "https://a.com/1", "BUY"),
+      offer(gb, "https://a.com/1", "RENT"),
+   }
+   new := []*EnrichedOffer{
+      offer(us, "https://a.com/1", "FLATRATE"),
+      offer(de, "https://a.com/1", "RENT"),
+   }
+   var data strings.Builder
+   _, err := Diff(old, new).WriteTo(&data)
+   if err != nil {
+      t.Fatal(err)
+   }
+   const want = `+ DE https://a.com/1 RENT
+- GB https://a.com/1 RENT
+~ US https://a.com/1 BUY,RENT -> FLATRATE
+`
+   if data.String() != want {
+      t.Fatal(data.String())
+   }
+}
+
+func TestDeduplicate(t *testing.T) {
+   us := &Locale{FullLocale: "en_US", Country: "US"}
+   offer := func(presentation string, price float64) *EnrichedOffer {
+      return &EnrichedOffer{Locale: us, Offer: &Offer{
+         MonetizationType: "BUY",
+         StandardWebUrl:   "https://a.com/1",
+         PresentationType: presentation,
+         RetailPriceValue: price,
+         Currency:         "USD",
+      }}
+   }
+   offers := Deduplicate([]*EnrichedOffer{
+      offer("_4K", 19.99), offer("SD", 9.99), offer("HD", 14.99),
+      offer("SD", 9.99), offer("HD", 12.99),
+   })
+   var got []string
+   for _, value := range offers {
+      got = append(got, fmt.Sprint(
+         value.Offer.PresentationType, " ", value.Offer.RetailPriceValue,
+      ))
+   }
+   if fmt.Sprint(got) != "[HD 12.99 HD 14.99 SD 9.99 _4K 19.99]" {
+      t.Fatal(got)
+   }
+}
+
+func TestCanonicalizer(t *testing.T) {
+   user_rules, err := ReadRules([]byte(`[
+      {"date": "2026-04-01", "kind": "param_prefix", "key": "utm_"},
+      {"date": "2026-04-01", "kind": "param", "key": "ref"},
+      {"date": "2026-04-01", "kind": "host_prefix", "key": "www."},
+      {"date": "2026-04-01", "kind": "host", "key": "m.a.com", "value": "a.com"},
+      {"date": "2026-04-01", "kind": "path", "key": "/+$"}
+   ]`))
+   if err != nil {
+      t.Fatal(err)
+   }
+   canonical := Canonicalizer{append(Canonical.Rules, user_rules...)}
+   tests := []struct {
+      in, out string
+   }{
+      {"https://a.com/1?utm_source=justwatch\n", "https://a.com/1"},
+      {"https://a.com/1?source=bing&b=2&a=1", "https://a.com/1?a=1&b=2"},
+      {"https://a.com/1?source=other", "https://a.com/1?source=other"},
+      {"https://www.a.com/1/?utm_foo=x&ref=y", "https://a.com/1"},
+      {"https://m.a.com/1//", "https://a.com/1"},
+   }
+   for _, test := range tests {
+      if out := canonical.Key(test.in); out != test.out {
+         t.Fatal(test.in, out)
+      }
+   }
+   // only the embedded rules
+   for in, out := range map[string]string{
+      tests[3].in:                           "https://www.a.com/1?ref=y",
+      "https://netflix.com/title/1/?utm_x=1": "https://www.netflix.com/title/1",
+      "https://a.com/?searchReferral=x":      "https://a.com/",
+   } {
+      if key := getUrlGroupingKey(in); key != out {
+         t.Fatal(in, key)
+      }
+   }
+   _, err = ReadRules([]byte(`[{"kind": "nope"}]`))
+   if err == nil {
+      t.Fatal("ReadRules")
+   }
+}
+
+func TestLoadCanonicalizer(t *testing.T) {
+   dir := t.TempDir()
+   name := filepath.Join(dir, "canonical.json")
+   err := os.WriteFile(name, []byte(`[
+      {"date": "2026-04-01", "kind": "param", "key": "ref"}
+   ]`), os.ModePerm)
+   if err != nil {
+      t.Fatal(err)
+   }
+   canonical, err := LoadCanonicalizer(name)
+   if err != nil {
+      t.Fatal(err)
+   }
+   if len(canonical.Rules) != len(Canonical.Rules)+1 {
+      t.Fatal(len(canonical.Rules))
+   }
+   defer func(old *Canonicalizer) {
+      Canonical = old
+   }(Canonical)
+   Canonical = canonical
+   key := getUrlGroupingKey("https://a.com/1?ref=y&utm_source=justwatch")
+   if key != "https://a.com/1" {
+      t.Fatal(key)
+   }
+   // a missing file is only the embedded rules
+   canonical, err = LoadCanonicalizer(filepath.Join(dir, "missing.json"))
+   if err != nil {
+      t.Fatal(err)
+   }
+   if len(canonical.Rules) != len(Canonical.Rules)-1 {
+      t.Fatal(len(canonical.Rules))
+   }
+   err = os.WriteFile(name, []byte(`[{"kind": "nope"}]`), os.ModePerm)
+   if err != nil {
+      t.Fatal(err)
+   }
+   if _, err = LoadCanonicalizer(name); err == nil {
+      t.Fatal("nope")
+   }
+}
+
+func TestNormalize(t *testing.T) {
+   tests := []struct {
+      in, out string
+   }{
+      {
+         "https://www.netflix.com/gb/title/80117401?source=35",
+         "https://www.netflix.com/title/80117401",
+      },
+      {
+         "https://tv.apple.com/us/movie/goodfellas/umc.cmc.3s4mgg2y7h95fks9gnc4pw13m",
+         "https://tv.apple.com/movie/umc.cmc.3s4mgg2y7h95fks9gnc4pw13m",
+      },
+      {
+         "https://www.disneyplus.com/en-gb/movies/the-muppets/7NiJDbNh5Oeb",
+         "https://www.disneyplus.com/movies/the-muppets/7NiJDbNh5Oeb",
+      },
+      {"https://mubi.com/en/gb/films/mulholland-drive", "https://mubi.com/films/mulholland-drive"},
+      {"https://www.netflix.com/browse", "https://www.netflix.com/browse"},
+      {"https://a.com/title/1", "https://a.com/title/1"},
+   }
+   for _, test := range tests {
+      if out := getUrlGroupingKey(test.in); out != test.out {
+         t.Fatal(test.in, out)
+      }
+   }
+   // as a host_prefix www. rule leaves them
+   for in, out := range map[string]string{
+      "https://primevideo.com/detail/Goodfellas/0KRGHGZCHKS9":        "https://www.primevideo.com/detail/0KRGHGZCHKS9",
+      "https://disneyplus.com/en-gb/movies/the-muppets/7NiJDbNh5Oeb": "https://www.disneyplus.com/movies/the-muppets/7NiJDbNh5Oeb",
+   } {
+      if key := Normalize(in); key != out {
+         t.Fatal(in, key)
+      }
+   }
+}
+
+func TestParseFilter(t *testing.T) {
+   offer := func(country, monetization, address string, count int) *EnrichedOffer {
+      return &EnrichedOffer{
+         Locale: &Locale{Country: country},
+         Offer: &Offer{
+            MonetizationType: monetization,
+            StandardWebUrl:   address,
+            ElementCount:     count,
+         },
+      }
+   }
+   offers := []*EnrichedOffer{
+      offer("US", "RENT", "https://www.netflix.com/title/1", 0),
+      offer("US", "FLATRATE", "https://www.netflix.com/title/1", 1),
+      offer("GB", "FLATRATE", "https://netflix.com/title/1", 3),
+      offer("DE", "FLATRATE", "https://a.com/1", 1),
+      offer("BR", "BUY", "https://a.com/1", 1),
+   }
+   tests := []struct {
+      expression string
+      want       int
+   }{
+      {"country in (US,GB) and not monetization=RENT", 2},
+      {"continent=EU or (host=netflix.com and count>=2)", 2},
+      {`Country != "us" AND NOT (continent = "SA")`, 2},
+      {"not (country=US or country=GB)", 2},
+      {"count<1", 1},
+   }
+   for _, test := range tests {
+      p, err := ParseFilter(test.expression)
+      if err != nil {
+         t.Fatal(test.expression, err)
+      }
+      if got := len(Filter(offers, p)); got != test.want {
+         t.Fatal(test.expression, got)
+      }
+   }
+   for _, expression := range []string{
+      "", "country", "country in (US", "colour=red", "count>x",
+      "country=US and", "country=US)", `country="US`, "country ! US",
+   } {
+      if _, err := ParseFilter(expression); err == nil {
+         t.Fatal(expression)
+      }
+   }
+}
+
+func TestRegion(t *testing.T) {
+   for _, locale_data := range EnUs {
+      if _, ok := locale_data.Region(); !ok {
+         t.Fatal(locale_data)
+      }
+   }
+   // 27 members, and French Guiana as part of France
+   eu := EnUs.Filter(RegionSets["eu"])
+   if len(eu) != 28 {
+      t.Fatal(len(eu))
+   }
+   set, err := LookupRegionSet("US,gb")
+   if err != nil {
+      t.Fatal(err)
+   }
+   if !set("GB") || set("IE") {
+      t.Fatal("LookupRegionSet")
+   }
+   p, err := ParseFilter("region in (english, eea) and region != eu")
+   if err != nil {
+      t.Fatal(err)
+   }
+   if !p(&EnrichedOffer{Locale: &Locale{Country: "NO"}}) {
+      t.Fatal("NO")
+   }
+   if p(&EnrichedOffer{Locale: &Locale{Country: "IE"}}) {
+      t.Fatal("IE")
+   }
+   if _, err = ParseFilter("region=atlantis"); err == nil {
+      t.Fatal("atlantis")
+   }
+}
+
+func TestProviders(t *testing.T) {
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         if req.URL.Path == "/graphql" {
+            fmt.Fprint(w, `{"errors":[{"message":"Cannot query field"}]}`)
+            return
+         }
+         if req.URL.Path != "/us" && req.URL.Path != "/uk" {
+            http.NotFound(w, req)
+            return
+         }
+         fmt.Fprint(w, `<script>window.__DATA__={"state":{"constant":{
+            "providers":[{"slug":"netflix","clearName":"Netflix","hasTitles":true}]
+         }}}</script>`)
+      },
+   ))
+   defer server.Close()
+   client := Client{BaseUrl: server.URL, WebUrl: server.URL}
+   providers, err := client.Providers("US")
+   if err != nil {
+      t.Fatal(err)
+   }
+   if len(providers) != 1 || providers[0].Slug != "netflix" {
+      t.Fatal(providers)
+   }
+   // the home page of GB is /uk
+   providers, err = client.Providers("GB")
+   if err != nil {
+      t.Fatal(err)
+   }
+   if len(providers) != 1 {
+      t.Fatal(providers)
+   }
+}
+
+func TestParseProviderUrl(t *testing.T) {
+   for _, test := range []struct {
+      url     string
+      country string
+      kind    string
+   }{
+      {"https://www.justwatch.com/se/leverantör/draken-films", "SE", "PROVIDER"},
+      {"https://www.justwatch.com/uk/provider/itvx", "GB", "PROVIDER"},
+      {"https://www.justwatch.com/us/movie/goodfellas", "US", "MOVIE"},
+      {"https://www.justwatch.com/us/tv-show/twin-peaks/season-1", "US", "SHOW_SEASON"},
+      {"https://www.justwatch.com/ar/pelicula/mulholland-drive", "AR", "MOVIE"},
+   } {
+      address, err := ParseProviderUrl(test.url)
+      if err != nil {
+         t.Fatal(err)
+      }
+      if address.Country != test.country || address.Kind != test.kind {
+         t.Fatal(address)
+      }
+   }
+   for _, bad := range []string{
+      "https://www.justwatch.com/fr/provider/canalplus",
+      "https://www.justwatch.com/es/plateforme/movistar-plus",
+      "https://www.justwatch.com/ar/provider/netflix",
+      "https://www.justwatch.com/jp/provider/u-next",
+      "https://www.justwatch.com/us/provider",
+      "https://www.justwatch.com/gb/provider/itvx",
+      "https://www.justwatch.com/us/movie/goodfellas/season-1",
+      "https://example.com/us/provider/netflix",
+      "justwatch.com/us/provider/netflix",
+   } {
+      _, err := ParseProviderUrl(bad)
+      if _, ok := errors.AsType[*UrlError](err); !ok {
+         t.Fatal(bad, err)
+      }
+   }
+}
+
+func TestTranslations(t *testing.T) {
+   hrefs := map[string][]string{
+      "/us/provider/netflix": {
+         "/us/provider/netflix", "/es/plataforma/netflix", "/jp/動画配信サービス/netflix",
+      },
+      "/us/movie/goodfellas": {
+         "/us/movie/goodfellas", "/es/pelicula/uno-de-los-nuestros", "/jp/映画/goodfellas",
+      },
+      "/us/tv-show/twin-peaks": {
+         "/us/tv-show/twin-peaks", "/es/serie/twin-peaks", "/jp/TV番組/twin-peaks",
+      },
+   }
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         var content Content
+         for _, href := range hrefs[req.URL.Query().Get("path")] {
+            content.HrefLangTags = append(
+               content.HrefLangTags, HrefLangTag{Href: href},
+            )
+         }
+         json.NewEncoder(w).Encode(content)
+      },
+   ))
+   defer server.Close()
+   translations, err := (&Client{BaseUrl: server.URL}).FetchTranslations(t.Context())
+   if err != nil {
+      t.Fatal(err)
+   }
+   want := Segments{Provider: "plataforma", Movie: "pelicula", Show: "serie"}
+   if translations["es"] != want || translations["us"] != KnownTranslations["us"] {
+      t.Fatal(translations)
+   }
+   address, err := translations.Parse(
+      "https://www.justwatch.com/jp/TV番組/twin-peaks/season-1",
+   )
+   if err != nil {
+      t.Fatal(err)
+   }
+   if address.Country != "JP" || address.Kind != "SHOW_SEASON" {
+      t.Fatal(address)
+   }
+   if _, err = translations.Parse("https://www.justwatch.com/es/film/x"); err == nil {
+      t.Fatal("es/film")
+   }
+}
+
+func TestCoverage(t *testing.T) {
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         var body struct {
+            Variables struct {
+               Country string
+            }
+         }
+         json.NewDecoder(req.Body).Decode(&body)
+         switch body.Variables.Country {
+         case "DE":
+            fmt.Fprint(w, `{"data":{"packages":[
+               {"slug":"mubi","hasTitles":true},
+               {"slug":"criterion-channel","hasTitles":false}
+            ]}}`)
+         case "GB":
+            fmt.Fprint(w, `{"data":{"packages":[
+               {"slug":"mubi","hasTitles":true},
+               {"slug":"criterion-channel","hasTitles":true}
+            ]}}`)
+         case "US":
+            fmt.Fprint(w, `{"data":{"packages":[
+               {"slug":"criterion-channel","hasTitles":true},
+               {"slug":"mubi","hasTitles":true}
+            ]}}`)
+         default:
+            http.Error(w, "", http.StatusBadRequest)
+         }
+      },
+   ))
+   defer server.Close()
+   client := Client{BaseUrl: server.URL, WebUrl: server.URL}
+   coverage, err := client.FetchCoverage(
+      t.Context(),
+      Locales{{Country: "US"}, {Country: "GB"}, {Country: "DE"}, {Country: "FR"}},
+      &FetchOptions{Workers: 2},
+   )
+   if _, ok := errors.AsType[*CountryError](err); !ok {
+      t.Fatal(err)
+   }
+   if !slices.Equal(coverage.Countries, []string{"DE", "GB", "US"}) {
+      t.Fatal(coverage.Countries)
+   }
+   have := coverage.Where("mubi", "criterion-channel")
+   if !slices.Equal(have, []string{"GB", "US"}) {
+      t.Fatal(have)
+   }
+   if coverage.Where("mubi", "netflix") != nil {
+      t.Fatal("netflix")
+   }
+}
+
+func TestOptimize(t *testing.T) {
+   us := &Locale{Country: "US"}
+   offer := func(href, provider, monetization string) *EnrichedOffer {
+      return &EnrichedOffer{
+         Href:   href,
+         Locale: us,
+         Offer: &Offer{
+            MonetizationType: monetization,
+            Package:          Package{TechnicalName: provider},
+         },
+      }
+   }
+   offers := []*EnrichedOffer{
+      offer("/us/movie/a", "alpha", "FLATRATE"),
+      offer("/us/movie/b", "alpha", "FLATRATE"),
+      offer("/us/movie/c", "beta", "FLATRATE"),
+      offer("/us/movie/a", "gamma", "FLATRATE"),
+      offer("/us/movie/b", "gamma", "FLATRATE"),
+      offer("/us/movie/c", "gamma", "FLATRATE"),
+      offer("/us/movie/a", "delta", "FREE"),
+      offer("/us/movie/d", "epsilon", "RENT"),
+      offer("/us/movie/e", "zeta", "FLATRATE"),
+   }
+   opts := OptimizeOptions{
+      Country: "US",
+      Prices:  map[string]float64{"alpha": 10, "beta": 5, "gamma": 20},
+   }
+   names := func(plan *Plan) []string {
+      var values []string
+      for _, value := range plan.Subscriptions {
+         values = append(values, value.TechnicalName)
+      }
+      return values
+   }
+   plan, err := Optimize(offers, &opts)
+   if err != nil {
+      t.Fatal(err)
+   }
+   // delta is free without a price, zeta is left out for having none
+   if !slices.Equal(names(plan), []string{"delta", "beta", "alpha"}) {
+      t.Fatal(names(plan))
+   }
+   if plan.Covered() != 3 || len(plan.Titles) != 4 || plan.Price != 15 {
+      t.Fatal(plan)
+   }
+   if !slices.Equal(plan.Unpriced, []string{"zeta"}) {
+      t.Fatal(plan.Unpriced)
+   }
+   opts.Exact = 8
+   plan, err = Optimize(offers, &opts)
+   if err != nil {
+      t.Fatal(err)
+   }
+   if !plan.Exact || !slices.Equal(names(plan), []string{"alpha", "beta"}) {
+      t.Fatal(names(plan))
+   }
+   opts.Prices = nil
+   plan, err = Optimize(offers, &opts)
+   if err != nil {
+      t.Fatal(err)
+   }
+   if !slices.Equal(names(plan), []string{"gamma", "zeta"}) {
+      t.Fatal(names(plan))
+   }
+   // offers from HrefLangTag.Offers have no Href
+   offers = append(offers, offer("", "alpha", "FLATRATE"))
+   if _, err = Optimize(offers, &opts); !errors.Is(err, ErrNoTitleKey) {
+      t.Fatal(err)
+   }
+   opts.Title = func(offer *EnrichedOffer) string {
+      return cmp.Or(offer.Href, "/us/movie/f")
+   }
+   plan, err = Optimize(offers, &opts)
+   if err != nil {
+      t.Fatal(err)
+   }
+   if len(plan.Titles) != 5 {
+      t.Fatal(len(plan.Titles))
+   }
+}
+
+func TestGrouping(t *testing.T) {
+   offer := func(address, country, monetization string) *EnrichedOffer {
+      return &EnrichedOffer{
+         Locale: &Locale{Country: country},
+         Offer: &Offer{
+            StandardWebUrl: address, MonetizationType: monetization,
+         },
+      }
+   }
+   offers := []*EnrichedOffer{
+      offer("https://www.netflix.com/title/1", "US", "FLATRATE"),
+      offer("https://mubi.com/films/a", "GB", "FLATRATE"),
+      offer("https://www.netflix.com/title/1", "CA", "FLATRATE"),
+      offer("https://mubi.com/films/a", "DE", "RENT"),
+      offer("https://www.netflix.com/title/1", "BR", "ADS"),
+   }
+   countries := func(group []*EnrichedOffer) string {
+      var values []string
+      for _, value := range group {
+         values = append(values, value.Locale.Country)
+      }
+      return strings.Join(values, ",")
+   }
+   // the order GroupAndSortByUrl has always had: shorter keys first, then
+   // offers by country
+   keys, groups := GroupAndSortByUrl(offers)
+   if len(keys) != 2 || len(keys[0]) > len(keys[1]) {
+      t.Fatal(keys)
+   }
+   for _, key := range keys {
+      switch countries(groups[key]) {
+      case "DE,GB", "BR,CA,US":
+      default:
+         t.Fatal(key, countries(groups[key]))
+      }
+   }
+   grouping := Grouping{
+      Key: ByMonetization, Groups: GroupSize, Offers: CountryOrder,
+   }
+   keys, groups = grouping.Group(offers)
+   if strings.Join(keys, ",") != "FLATRATE,ADS,RENT" {
+      t.Fatal(keys)
+   }
+   if countries(groups["FLATRATE"]) != "CA,GB,US" {
+      t.Fatal(countries(groups["FLATRATE"]))
+   }
+   // a nil Groups sorts the keys
+   keys, groups = (&Grouping{Key: ByCountry}).Group(offers)
+   if strings.Join(keys, ",") != "BR,CA,DE,GB,US" || len(groups["US"]) != 1 {
+      t.Fatal(keys)
+   }
+}
+
+func TestSeasons(t *testing.T) {
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         fmt.Fprint(w, `{"data":{"url":{"node":{"seasons":[
+            {"content":{"seasonNumber":2},
+            "offers":[{"monetizationType":"FLATRATE"}],
+            "episodes":[]},
+            {"content":{"seasonNumber":1},
+            "offers":[{"monetizationType":"RENT"}],
+            "episodes":[
+               {"content":{"seasonNumber":1,"episodeNumber":2},
+               "offers":[{"monetizationType":"BUY"}]},
+               {"content":{"seasonNumber":1,"episodeNumber":1},"offers":[]}
+            ]}
+         ]}}}}`)
+      },
+   ))
+   defer server.Close()
+   client := Client{BaseUrl: server.URL}
+   us := &Locale{Country: "US", FullLocale: "en_US"}
+   seasons, err := client.Seasons(&HrefLangTag{Href: "/us/tv-show/twin-peaks"}, us)
+   if err != nil {
+      t.Fatal(err)
+   }
+   offers := SeasonOffers(us, seasons)
+   if len(offers) != 3 {
+      t.Fatal(len(offers))
+   }
+   offers = append(offers, &EnrichedOffer{
+      Locale: us, Offer: &Offer{MonetizationType: "ADS"},
+   })
+   numbers, groups := GroupBySeason(offers)
+   if fmt.Sprint(numbers) != "[0 1 2]" {
+      t.Fatal(numbers)
+   }
+   season_1 := groups[1]
+   if len(season_1) != 2 || season_1[0].Episode != 0 ||
+      season_1[1].Episode != 2 || season_1[1].Offer.MonetizationType != "BUY" {
+      t.Fatal(season_1)
+   }
+   keys, _ := SeasonGrouping.Group(offers)
+   if strings.Join(keys, ",") != "title,season 1,season 2" {
+      t.Fatal(keys)
+   }
+}
+
+func TestResolve(t *testing.T) {
+   for data, want := range map[string]ExternalId{
+      "tt0099685":      {Source: "imdb", Id: "tt0099685"},
+      "tmdb:movie/769": {Source: "tmdb_movie", Id: "769"},
+      "tmdb:tv/1920":   {Source: "tmdb_show", Id: "1920"},
+   } {
+      id, err := ParseExternalId(data)
+      if err != nil {
+         t.Fatal(err)
+      }
+      if *id != want {
+         t.Fatal(id)
+      }
+   }
+   for _, data := range []string{"769", "tmdb:movie/", "tmdb:person/1"} {
+      if _, err := ParseExternalId(data); err == nil {
+         t.Fatal(data)
+      }
+   }
+   var queries []string
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         if req.URL.Path == "/content/urls" {
+            if req.URL.Query().Get("path") != "/us/movie/goodfellas" {
+               t.Error(req.URL)
+            }
+            fmt.Fprint(w, `{"href_lang_tags":[{"locale":"en_US"}]}`)
+            return
+         }
+         var body struct {
+            Variables struct {
+               SearchQuery string
+            }
+         }
+         json.NewDecoder(req.Body).Decode(&body)
+         queries = append(queries, body.Variables.SearchQuery)
+         const movie = `{"node":{"objectType":"MOVIE","content":{
+            "fullPath":"/us/movie/goodfellas",
+            "externalIds":{"imdbId":"tt0099685","tmdbId":"769"}
+         }}}`
+         switch body.Variables.SearchQuery {
+         case "tt0099685":
+            fmt.Fprint(w, `{"data":{"popularTitles":{"edges":[`+movie+`]}}}`)
+         case "Goodfellas":
+            fmt.Fprint(w, `{"data":{"popularTitles":{"edges":[
+               {"node":{"objectType":"SHOW","content":{
+                  "fullPath":"/us/tv-show/goodfellas",
+                  "externalIds":{"imdbId":"tt1","tmdbId":"769"}
+               }}},`+movie+`
+            ]}}}`)
+         default:
+            fmt.Fprint(w, `{"data":{"popularTitles":{"edges":[]}}}`)
+         }
+      },
+   ))
+   defer server.Close()
+   client := Client{BaseUrl: server.URL}
+   tests := []struct {
+      id      ExternalId
+      found   bool
+      queries string
+   }{
+      {ExternalId{Source: "imdb", Id: "tt0099685"}, true, "tt0099685"},
+      {
+         ExternalId{Source: "tmdb_movie", Id: "769", Title: "Goodfellas"},
+         true, "769,Goodfellas",
+      },
+      {
+         ExternalId{Source: "tmdb_show", Id: "1920", Title: "Goodfellas"},
+         false, "1920,Goodfellas",
+      },
+      {ExternalId{Source: "tmdb_show", Id: "1920"}, false, "1920"},
+   }
+   for _, test := range tests {
+      queries = nil
+      title, content, err := client.Resolve(&test.id, "US")
+      if strings.Join(queries, ",") != test.queries {
+         t.Fatal(test.id, queries)
+      }
+      if !test.found {
+         if !errors.Is(err, ErrNotFound) {
+            t.Fatal(test.id, err)
+         }
+         continue
+      }
+      if err != nil {
+         t.Fatal(err)
+      }
+      if title.Content.FullPath != "/us/movie/goodfellas" ||
+         len(content.HrefLangTags) != 1 {
+         t.Fatal(title, content)
+      }
+   }
+}
@@ -0,0 +1,78 @@
+package justWatch
+
+import (
+   "net/url"
+   "regexp"
+   "strings"
+)
+
+// Normalizer returns an address for the content that is the same in every
+// country, or false if it does not know the form of address
+type Normalizer func(address *url.URL) (string, bool)
+
+var normalizers = map[string]Normalizer{}
+
+// RegisterNormalizer sets the Normalizer for host, with or without www.,
+// replacing any before it. Call it before grouping, for example from init
+func RegisterNormalizer(host string, normalize Normalizer) {
+   normalizers[strings.TrimPrefix(host, "www.")] = normalize
+}
+
+// Normalize returns rawUrl as its registered Normalizer maps it, else
+// rawUrl
+func Normalize(rawUrl string) string {
+   address, err := url.Parse(rawUrl)
+   if err != nil {
+      return rawUrl
+   }
+   // a host_prefix rule may have removed www.
+   normalize, ok := normalizers[strings.TrimPrefix(address.Host, "www.")]
+   if !ok {
+      return rawUrl
+   }
+   if content, ok := normalize(address); ok {
+      return content
+   }
+   return rawUrl
+}
+
+// PathNormalizer matches pattern against the path, and expands template
+// with the submatches as in regexp.Regexp.Expand
+func PathNormalizer(pattern, template string) Normalizer {
+   path := regexp.MustCompile(pattern)
+   return func(address *url.URL) (string, bool) {
+      match := path.FindStringSubmatchIndex(address.Path)
+      if match == nil {
+         return "", false
+      }
+      return string(path.ExpandString(nil, template, address.Path, match)), true
+   }
+}
+
+func init() {
+   // /gb/title/80117401 or /title/80117401
+   RegisterNormalizer("www.netflix.com", PathNormalizer(
+      `^(?:/[a-z]{2}(?:-[a-z]{2})?)?/title/(\d+)`,
+      "https://www.netflix.com/title/$1",
+   ))
+   // /us/movie/goodfellas/umc.cmc.3s4mgg2y7h95fks9gnc4pw13m
+   RegisterNormalizer("tv.apple.com", PathNormalizer(
+      `^(?:/[a-z]{2})?/(movie|show|episode)/[^/]+/(umc\.[\w.]+)`,
+      "https://tv.apple.com/$1/$2",
+   ))
+   // /region/eu/detail/0KRGHGZCHKS9 or /detail/Goodfellas/0KRGHGZCHKS9
+   RegisterNormalizer("www.primevideo.com", PathNormalizer(
+      `^(?:/region/\w+)?/detail/(?:[^/]+/)?(\w{10,})`,
+      "https://www.primevideo.com/detail/$1",
+   ))
+   // /en-gb/movies/the-muppets/7NiJDbNh5Oeb
+   RegisterNormalizer("www.disneyplus.com", PathNormalizer(
+      `^(?:/[a-z]{2}-[a-z]{2})?/(movies|series)/([^/]+)/(\w+)`,
+      "https://www.disneyplus.com/$1/$2/$3",
+   ))
+   // /en/gb/films/mulholland-drive or /films/mulholland-drive
+   RegisterNormalizer("mubi.com", PathNormalizer(
+      `^(?:/[a-z]{2}(?:/[a-z]{2})?)?/films/([^/]+)`,
+      "https://mubi.com/films/$1",
+   ))
+}
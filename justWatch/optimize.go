@@ -0,0 +1,294 @@
+package justWatch
+
+import (
+   "cmp"
+   "errors"
+   "math/bits"
+   "slices"
+   "strings"
+)
+
+// ErrNoTitleKey is returned by Optimize for an offer whose title key is empty
+var ErrNoTitleKey = errors.New("offer has no title key")
+
+// ByHref is the default title key of Optimize. Client.FetchOffers sets Href;
+// set it yourself for offers from HrefLangTag.Offers
+func ByHref(offer *EnrichedOffer) string {
+   return offer.Href
+}
+
+type OptimizeOptions struct {
+   Country      string   // US
+   Monetization []string // nil for FLATRATE, FREE and ADS
+   Title        KeyFunc  // identifies the title of an offer, nil for ByHref
+   // monthly price by technical name. nil to minimize the number of
+   // providers instead. Otherwise a provider that is missing has an unknown
+   // price, and is left out unless all of its offers are FREE or ADS
+   Prices map[string]float64
+   // use the exact solver if there are at most this many candidate
+   // providers, up to 16. Zero for the greedy solver only
+   Exact int
+}
+
+// Subscription is one provider of a Plan
+type Subscription struct {
+   TechnicalName string   // netflix
+   ClearName     string   // Netflix
+   Price         float64  // from OptimizeOptions.Prices
+   Titles        []string // keys of the titles it covers, sorted
+}
+
+// TitleCoverage is one title of a Plan
+type TitleCoverage struct {
+   Title     string   // key, by default the Href /us/movie/goodfellas
+   Providers []string // technical names of the chosen providers, empty if none
+}
+
+type Plan struct {
+   Subscriptions []*Subscription // in the order they were chosen
+   Titles        []*TitleCoverage // every title with an offer, sorted by key
+   Price         float64
+   Exact         bool     // false if the greedy solver was used
+   Unpriced      []string // technical names left out for having no price
+}
+
+// Covered returns the number of titles with at least one provider
+func (p *Plan) Covered() int {
+   var n int
+   for _, title := range p.Titles {
+      if len(title.Providers) >= 1 {
+         n++
+      }
+   }
+   return n
+}
+
+// title_set is a bit set of title indexes
+type title_set []uint64
+
+func (t title_set) add(i int) {
+   t[i/64] |= 1 << (i % 64)
+}
+
+func (t title_set) has(i int) bool {
+   return t[i/64]&(1<<(i%64)) != 0
+}
+
+func (t title_set) or(u title_set) title_set {
+   v := slices.Clone(t)
+   for i := range u {
+      v[i] |= u[i]
+   }
+   return v
+}
+
+// count of u that is not in t
+func (t title_set) gain(u title_set) int {
+   var n int
+   for i := range u {
+      n += bits.OnesCount64(u[i] &^ t[i])
+   }
+   return n
+}
+
+type candidate struct {
+   subscription *Subscription
+   titles       title_set
+   free         bool // every offer is FREE or ADS
+}
+
+// Optimize chooses providers in one country that cover as many of the titles
+// of offers as possible, then as cheaply as possible. Titles are identified by
+// opts.Title, and ErrNoTitleKey is returned if it is empty for an offer. The
+// greedy solver picks the provider with the most new titles per price until
+// no provider adds a title; the exact solver tries every set of providers
+func Optimize(offers []*EnrichedOffer, opts *OptimizeOptions) (*Plan, error) {
+   types := opts.Monetization
+   if types == nil {
+      types = []string{"FLATRATE", "FREE", "ADS"}
+   }
+   title := opts.Title
+   if title == nil {
+      title = ByHref
+   }
+   offers = Filter(offers, And(Country(opts.Country), Monetization(types...)))
+   var keys []string
+   for _, offer := range offers {
+      key := title(offer)
+      if key == "" {
+         return nil, ErrNoTitleKey
+      }
+      keys = append(keys, key)
+   }
+   slices.Sort(keys)
+   keys = slices.Compact(keys)
+   words := (len(keys) + 63) / 64
+   var candidates []*candidate
+   providers := map[string]*candidate{}
+   for _, offer := range offers {
+      name := offer.Offer.Package.TechnicalName
+      value := providers[name]
+      if value == nil {
+         value = &candidate{
+            subscription: &Subscription{
+               TechnicalName: name,
+               ClearName:     offer.Offer.Package.ClearName,
+               Price:         opts.Prices[name],
+            },
+            titles: make(title_set, words),
+            free:   true,
+         }
+         providers[name] = value
+         candidates = append(candidates, value)
+      }
+      switch offer.Offer.MonetizationType {
+      case "FREE", "ADS":
+      default:
+         value.free = false
+      }
+      i, _ := slices.BinarySearch(keys, title(offer))
+      value.titles.add(i)
+   }
+   slices.SortFunc(candidates, func(a, b *candidate) int {
+      return strings.Compare(
+         a.subscription.TechnicalName, b.subscription.TechnicalName,
+      )
+   })
+   plan := Plan{}
+   if opts.Prices != nil {
+      candidates = slices.DeleteFunc(candidates, func(c *candidate) bool {
+         _, ok := opts.Prices[c.subscription.TechnicalName]
+         if ok || c.free {
+            return false
+         }
+         plan.Unpriced = append(plan.Unpriced, c.subscription.TechnicalName)
+         return true
+      })
+   }
+   var (
+      chosen []*candidate
+      exact  bool
+   )
+   if opts.Exact >= 1 && len(candidates) <= min(opts.Exact, 16) {
+      chosen = exact_cover(candidates, words, opts.Prices != nil)
+      exact = true
+   } else {
+      chosen = greedy_cover(candidates, words, opts.Prices != nil)
+   }
+   plan.Exact = exact
+   for _, key := range keys {
+      plan.Titles = append(plan.Titles, &TitleCoverage{Title: key})
+   }
+   for _, value := range chosen {
+      for i, title := range plan.Titles {
+         if value.titles.has(i) {
+            value.subscription.Titles = append(
+               value.subscription.Titles, title.Title,
+            )
+            title.Providers = append(
+               title.Providers, value.subscription.TechnicalName,
+            )
+         }
+      }
+      plan.Subscriptions = append(plan.Subscriptions, value.subscription)
+      plan.Price += value.subscription.Price
+   }
+   return &plan, nil
+}
+
+// cost is the price, or one per provider without prices
+func (c *candidate) cost(priced bool) float64 {
+   if priced {
+      return c.subscription.Price
+   }
+   return 1
+}
+
+func greedy_cover(candidates []*candidate, words int, priced bool) []*candidate {
+   covered := make(title_set, words)
+   var chosen []*candidate
+   for {
+      var (
+         best      *candidate
+         best_gain int
+      )
+      for _, value := range candidates {
+         gain := covered.gain(value.titles)
+         if gain == 0 {
+            continue
+         }
+         if best == nil || better(value, gain, best, best_gain, priced) {
+            best, best_gain = value, gain
+         }
+      }
+      if best == nil {
+         return chosen
+      }
+      chosen = append(chosen, best)
+      covered = covered.or(best.titles)
+   }
+}
+
+// better compares new titles per cost. Free providers come first
+func better(a *candidate, a_gain int, b *candidate, b_gain int, priced bool) bool {
+   a_cost, b_cost := a.cost(priced), b.cost(priced)
+   switch {
+   case a_cost <= 0 && b_cost <= 0:
+      return a_gain > b_gain
+   case a_cost <= 0:
+      return true
+   case b_cost <= 0:
+      return false
+   }
+   return float64(a_gain)*b_cost > float64(b_gain)*a_cost
+}
+
+// exact_cover tries every subset. Among those that cover every title any
+// candidate covers, it takes the lowest cost, then the fewest providers
+func exact_cover(candidates []*candidate, words int, priced bool) []*candidate {
+   n := len(candidates)
+   unions := make([]title_set, 1<<n)
+   unions[0] = make(title_set, words)
+   all := unions[0]
+   for _, value := range candidates {
+      all = all.or(value.titles)
+   }
+   var (
+      best      = -1
+      best_cost float64
+   )
+   for mask := 1; mask < 1<<n; mask++ {
+      low := bits.TrailingZeros(uint(mask))
+      unions[mask] = unions[mask&(mask-1)].or(candidates[low].titles)
+      if unions[mask].gain(all) >= 1 {
+         continue
+      }
+      var cost float64
+      for i, value := range candidates {
+         if mask&(1<<i) != 0 {
+            cost += value.cost(priced)
+         }
+      }
+      if best == -1 || cmp.Or(
+         cmp.Compare(cost, best_cost),
+         bits.OnesCount(uint(mask))-bits.OnesCount(uint(best)),
+      ) < 0 {
+         best, best_cost = mask, cost
+      }
+   }
+   var chosen []*candidate
+   for i, value := range candidates {
+      if best >= 1 && best&(1<<i) != 0 {
+         chosen = append(chosen, value)
+      }
+   }
+   // most titles first, as greedy_cover would
+   slices.SortStableFunc(chosen, func(a, b *candidate) int {
+      return b.count() - a.count()
+   })
+   return chosen
+}
+
+func (c *candidate) count() int {
+   return make(title_set, len(c.titles)).gain(c.titles)
+}
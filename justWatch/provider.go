@@ -0,0 +1,114 @@
+package justWatch
+
+import (
+   "bytes"
+   "context"
+   _ "embed"
+   "encoding/json"
+   "errors"
+   "strings"
+)
+
+//go:embed GetProviders.gql
+var get_providers string
+
+// Providers is a wrapper for DefaultClient.Providers
+func Providers(country string) ([]Package, error) {
+   return DefaultClient.Providers(country)
+}
+
+// ProvidersContext is a wrapper for DefaultClient.ProvidersContext
+func ProvidersContext(ctx context.Context, country string) ([]Package, error) {
+   return DefaultClient.ProvidersContext(ctx, country)
+}
+
+// Providers returns the provider catalog of country, for example "US". It
+// uses the GraphQL packages query, and falls back to the state embedded in
+// the justwatch.com home page of the country if the query fails
+func (c *Client) Providers(country string) ([]Package, error) {
+   return c.ProvidersContext(context.Background(), country)
+}
+
+func (c *Client) ProvidersContext(
+   ctx context.Context, country string,
+) ([]Package, error) {
+   var result struct {
+      Packages []Package
+   }
+   err := c.graphql(
+      ctx,
+      get_providers,
+      map[string]string{"country": strings.ToUpper(country)},
+      &result,
+   )
+   if err == nil {
+      return result.Packages, nil
+   }
+   if ctx.Err() != nil {
+      return nil, err
+   }
+   providers, web_err := c.web_providers(ctx, country)
+   if web_err != nil {
+      return nil, errors.Join(err, web_err)
+   }
+   return providers, nil
+}
+
+// JustWatch uses uk instead of gb in paths
+func path_prefix(country string) string {
+   if strings.EqualFold(country, "GB") {
+      return "uk"
+   }
+   return strings.ToLower(country)
+}
+
+func (c *Client) web_providers(
+   ctx context.Context, country string,
+) ([]Package, error) {
+   base := c.WebUrl
+   if base == "" {
+      base = "https://www.justwatch.com"
+   }
+   req, err := c.new_request_at(
+      ctx, base, "GET", path_prefix(country), nil,
+   )
+   if err != nil {
+      return nil, err
+   }
+   data, err := c.do(req)
+   if err != nil {
+      return nil, err
+   }
+   _, data, found := bytes.Cut(data, []byte("window.__DATA__="))
+   if !found {
+      return nil, errors.New("window.__DATA__= not found")
+   }
+   data, _, found = bytes.Cut(data, []byte("</script>"))
+   if !found {
+      return nil, errors.New("</script> not found")
+   }
+   var state struct {
+      State struct {
+         Constant struct {
+            Providers []Package
+         }
+      }
+   }
+   err = json.Unmarshal(data, &state)
+   if err != nil {
+      return nil, err
+   }
+   return state.State.Constant.Providers, nil
+}
+
+// IconUrl returns the full icon address, for example with profile "s100"
+// and format "png"
+func (p *Package) IconUrl(profile, format string) string {
+   if p.Icon == "" {
+      return ""
+   }
+   icon := strings.NewReplacer(
+      "{profile}", profile, "{format}", format,
+   ).Replace(p.Icon)
+   return "https://images.justwatch.com" + icon
+}
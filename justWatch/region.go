@@ -0,0 +1,301 @@
+package justWatch
+
+import (
+   "errors"
+   "slices"
+   "strings"
+)
+
+type Region struct {
+   Continent string   // AF, AS, EU, NA, OC or SA
+   Eu        bool     // European Union
+   Eea       bool     // European Economic Area, true if Eu is
+   Currency  string   // ISO 4217, EUR
+   Languages []string // ISO 639-1, primary first
+}
+
+// country continent membership currency languages. Transcontinental
+// countries are listed where JustWatch users would look for them: CY and RU
+// in EU, TR in AS
+const region_table = `
+AD EU - EUR ca
+AE AS - AED ar
+AG NA - XCD en
+AL EU - ALL sq
+AO AF - AOA pt
+AR SA - ARS es
+AT EU eu EUR de
+AU OC - AUD en
+AZ AS - AZN az
+BA EU - BAM bs hr sr
+BB NA - BBD en
+BE EU eu EUR nl fr de
+BF AF - XOF fr
+BG EU eu EUR bg
+BH AS - BHD ar
+BM NA - BMD en
+BO SA - BOB es
+BR SA - BRL pt
+BS NA - BSD en
+BY EU - BYN be ru
+BZ NA - BZD en
+CA NA - CAD en fr
+CD AF - CDF fr
+CH EU - CHF de fr it
+CI AF - XOF fr
+CL SA - CLP es
+CM AF - XAF fr en
+CO SA - COP es
+CR NA - CRC es
+CU NA - CUP es
+CV AF - CVE pt
+CY EU eu EUR el tr
+CZ EU eu CZK cs
+DE EU eu EUR de
+DK EU eu DKK da
+DO NA - DOP es
+DZ AF - DZD ar
+EC SA - USD es
+EE EU eu EUR et
+EG AF - EGP ar
+ES EU eu EUR es
+FI EU eu EUR fi sv
+FJ OC - FJD en
+FR EU eu EUR fr
+GB EU - GBP en
+GF SA eu EUR fr
+GG EU - GBP en
+GH AF - GHS en
+GI EU - GIP en
+GQ AF - XAF es
+GR EU eu EUR el
+GT NA - GTQ es
+GY SA - GYD en
+HK AS - HKD zh en
+HN NA - HNL es
+HR EU eu EUR hr
+HU EU eu HUF hu
+ID AS - IDR id
+IE EU eu EUR en ga
+IL AS - ILS he
+IN AS - INR hi en
+IQ AS - IQD ar ku
+IS EU eea ISK is
+IT EU eu EUR it
+JM NA - JMD en
+JO AS - JOD ar
+JP AS - JPY ja
+KE AF - KES sw en
+KR AS - KRW ko
+KW AS - KWD ar
+LB AS - LBP ar
+LC NA - XCD en
+LI EU eea CHF de
+LT EU eu EUR lt
+LU EU eu EUR lb fr de
+LV EU eu EUR lv
+LY AF - LYD ar
+MA AF - MAD ar
+MC EU - EUR fr
+MD EU - MDL ro
+ME EU - EUR sr
+MG AF - MGA mg fr
+MK EU - MKD mk
+ML AF - XOF fr
+MT EU eu EUR mt en
+MU AF - MUR en fr
+MW AF - MWK en ny
+MX NA - MXN es
+MY AS - MYR ms
+MZ AF - MZN pt
+NE AF - XOF fr
+NG AF - NGN en
+NI NA - NIO es
+NL EU eu EUR nl
+NO EU eea NOK no
+NZ OC - NZD en
+OM AS - OMR ar
+PA NA - USD es
+PE SA - PEN es
+PF OC - XPF fr
+PG OC - PGK en
+PH AS - PHP en tl
+PK AS - PKR ur en
+PL EU eu PLN pl
+PS AS - ILS ar
+PT EU eu EUR pt
+PY SA - PYG es gn
+QA AS - QAR ar
+RO EU eu RON ro
+RS EU - RSD sr
+RU EU - RUB ru
+SA AS - SAR ar
+SC AF - SCR en fr
+SE EU eu SEK sv
+SG AS - SGD en ms zh ta
+SI EU eu EUR sl
+SK EU eu EUR sk
+SM EU - EUR it
+SN AF - XOF fr
+SV NA - USD es
+TC NA - USD en
+TD AF - XAF fr ar
+TH AS - THB th
+TN AF - TND ar
+TR AS - TRY tr
+TT NA - TTD en
+TW AS - TWD zh
+TZ AF - TZS sw en
+UA EU - UAH uk
+UG AF - UGX en sw
+US NA - USD en
+UY SA - UYU es
+VA EU - EUR it la
+VE SA - VES es
+XK EU - EUR sq sr
+YE AS - YER ar
+ZA AF - ZAR en af zu
+ZM AF - ZMW en
+ZW AF - USD en
+`
+
+var regions = map[string]*Region{}
+
+func init() {
+   for line := range strings.Lines(region_table) {
+      fields := strings.Fields(line)
+      if len(fields) == 0 {
+         continue
+      }
+      regions[fields[0]] = &Region{
+         Continent: fields[1],
+         Eu:        fields[2] == "eu",
+         Eea:       fields[2] == "eu" || fields[2] == "eea",
+         Currency:  fields[3],
+         Languages: fields[4:],
+      }
+   }
+}
+
+// CountryRegion returns false if country is unknown
+func CountryRegion(country string) (*Region, bool) {
+   region, ok := regions[strings.ToUpper(country)]
+   return region, ok
+}
+
+func (l *Locale) Region() (*Region, bool) {
+   return CountryRegion(l.Country)
+}
+
+// Continent returns AF, AS, EU, NA, OC or SA, or "" if country is unknown
+func Continent(country string) string {
+   if region, ok := CountryRegion(country); ok {
+      return region.Continent
+   }
+   return ""
+}
+
+// RegionSet reports whether a country is in the set
+type RegionSet func(country string) bool
+
+func region_set(match func(*Region) bool) RegionSet {
+   return func(country string) bool {
+      region, ok := CountryRegion(country)
+      return ok && match(region)
+   }
+}
+
+func ContinentSet(continent string) RegionSet {
+   return region_set(func(r *Region) bool {
+      return strings.EqualFold(r.Continent, continent)
+   })
+}
+
+// LanguageSet holds countries where language, such as en, is primary
+func LanguageSet(language string) RegionSet {
+   return region_set(func(r *Region) bool {
+      return slices.Contains(r.Languages, strings.ToLower(language))
+   })
+}
+
+func CurrencySet(currency string) RegionSet {
+   return region_set(func(r *Region) bool {
+      return strings.EqualFold(r.Currency, currency)
+   })
+}
+
+var RegionSets = map[string]RegionSet{
+   "africa":        ContinentSet("AF"),
+   "asia":          ContinentSet("AS"),
+   "europe":        ContinentSet("EU"),
+   "north-america": ContinentSet("NA"),
+   "oceania":       ContinentSet("OC"),
+   "south-america": ContinentSet("SA"),
+   "eu":            region_set(func(r *Region) bool { return r.Eu }),
+   "eea":           region_set(func(r *Region) bool { return r.Eea }),
+   "eurozone":      CurrencySet("EUR"),
+   "english":       LanguageSet("en"),
+   "french":        LanguageSet("fr"),
+   "german":        LanguageSet("de"),
+   "portuguese":    LanguageSet("pt"),
+   "spanish":       LanguageSet("es"),
+   "arabic":        LanguageSet("ar"),
+}
+
+// LookupRegionSet accepts a name from RegionSets, or continent:EU,
+// language:en, currency:EUR, or country codes such as US,GB,IE
+func LookupRegionSet(name string) (RegionSet, error) {
+   if set, ok := RegionSets[strings.ToLower(name)]; ok {
+      return set, nil
+   }
+   kind, value, ok := strings.Cut(name, ":")
+   if ok {
+      switch strings.ToLower(kind) {
+      case "continent":
+         return ContinentSet(value), nil
+      case "language":
+         return LanguageSet(value), nil
+      case "currency":
+         return CurrencySet(value), nil
+      }
+   }
+   countries := strings.Split(strings.ToUpper(name), ",")
+   for _, country := range countries {
+      if _, ok := regions[country]; !ok {
+         return nil, errors.New("unknown region: " + name)
+      }
+   }
+   return func(country string) bool {
+      return slices.Contains(countries, strings.ToUpper(country))
+   }, nil
+}
+
+// Filter returns the locales in set
+func (l Locales) Filter(set RegionSet) Locales {
+   var locales Locales
+   for _, locale_data := range l {
+      if set(locale_data.Country) {
+         locales = append(locales, locale_data)
+      }
+   }
+   return locales
+}
+
+// InRegion keeps offers in any of the region sets, named as for
+// LookupRegionSet. An unknown name matches nothing
+func InRegion(names ...string) Predicate {
+   var sets []RegionSet
+   for _, name := range names {
+      if set, err := LookupRegionSet(name); err == nil {
+         sets = append(sets, set)
+      }
+   }
+   return func(offer *EnrichedOffer) bool {
+      for _, set := range sets {
+         if set(offer.Locale.Country) {
+            return true
+         }
+      }
+      return false
+   }
+}
@@ -0,0 +1,222 @@
+// Package report renders the output of justWatch.GroupAndSortByUrl, or of
+// any justWatch.Grouping
+package report
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "bytes"
+   _ "embed"
+   "encoding/csv"
+   "encoding/json"
+   "errors"
+   "html/template"
+   "io"
+   "strconv"
+)
+
+//go:embed report.html
+var report_html string
+
+var html_template = template.Must(template.New("report").Parse(report_html))
+
+type Report struct {
+   Title  *justWatch.TitleDetails // nil to leave out
+   Keys   []string
+   Groups map[string][]*justWatch.EnrichedOffer
+}
+
+// Formats maps a format name to its file extension
+var Formats = map[string]string{
+   "csv":      ".csv",
+   "html":     ".html",
+   "json":     ".json",
+   "markdown": ".md",
+}
+
+func (r *Report) Write(w io.Writer, format string) error {
+   switch format {
+   case "csv":
+      return r.Csv(w)
+   case "html":
+      return r.Html(w)
+   case "json":
+      return r.Json(w)
+   case "markdown":
+      return r.Markdown(w)
+   }
+   return errors.New("unknown format: " + format)
+}
+
+// Row is one EnrichedOffer, flattened
+type Row struct {
+   Group        string  `json:"-"`
+   Url          string  `json:"url"` // StandardWebUrl, not the group key
+   Country      string  `json:"country"`
+   Name         string  `json:"name"`
+   Season       int     `json:"season,omitzero"`
+   Episode      int     `json:"episode,omitzero"`
+   Provider     string  `json:"provider"`
+   Monetization string  `json:"monetization"`
+   Presentation string  `json:"presentation,omitzero"`
+   Price        float64 `json:"price,omitzero"`
+   Currency     string  `json:"currency,omitzero"`
+   Count        int     `json:"count,omitzero"`
+}
+
+func NewRow(key string, enriched *justWatch.EnrichedOffer) *Row {
+   return &Row{
+      Group:        key,
+      Url:          enriched.Offer.StandardWebUrl,
+      Country:      enriched.Locale.Country,
+      Name:         enriched.Locale.CountryName,
+      Season:       enriched.Season,
+      Episode:      enriched.Episode,
+      Provider:     enriched.Offer.Package.ClearName,
+      Monetization: enriched.Offer.MonetizationType,
+      Presentation: enriched.Offer.PresentationType,
+      Price:        enriched.Offer.RetailPriceValue,
+      Currency:     enriched.Offer.Currency,
+      Count:        enriched.Offer.ElementCount,
+   }
+}
+
+// Rows returns every offer in group order
+func (r *Report) Rows() []*Row {
+   var rows []*Row
+   for _, key := range r.Keys {
+      for _, enriched := range r.Groups[key] {
+         rows = append(rows, NewRow(key, enriched))
+      }
+   }
+   return rows
+}
+
+func (r *Report) heading() string {
+   if r.Title == nil {
+      return ""
+   }
+   heading := r.Title.Content.Title
+   if r.Title.Content.OriginalReleaseYear >= 1 {
+      heading += " (" + strconv.Itoa(r.Title.Content.OriginalReleaseYear) + ")"
+   }
+   return heading
+}
+
+func format_price(row *Row) string {
+   if row.Price <= 0 {
+      return ""
+   }
+   return strconv.FormatFloat(row.Price, 'f', 2, 64) + " " + row.Currency
+}
+
+func (r *Report) Markdown(w io.Writer) error {
+   var data bytes.Buffer
+   if heading := r.heading(); heading != "" {
+      data.WriteString("# ")
+      data.WriteString(heading)
+      data.WriteString("\n\n")
+   }
+   for i, key := range r.Keys {
+      if i >= 1 {
+         data.WriteString("\n\n")
+      }
+      data.WriteString("## ")
+      data.WriteString(key)
+      for _, enriched := range r.Groups[key] {
+         row := NewRow(key, enriched)
+         data.WriteByte('\n')
+         data.WriteString("\ncountry = ")
+         data.WriteString(row.Country)
+         data.WriteString("\nname = ")
+         data.WriteString(row.Name)
+         if row.Season >= 1 {
+            data.WriteString("\nseason = ")
+            data.WriteString(strconv.Itoa(row.Season))
+         }
+         if row.Episode >= 1 {
+            data.WriteString("\nepisode = ")
+            data.WriteString(strconv.Itoa(row.Episode))
+         }
+         data.WriteString("\nprovider = ")
+         data.WriteString(row.Provider)
+         data.WriteString("\nmonetization = ")
+         data.WriteString(row.Monetization)
+         if row.Presentation != "" {
+            data.WriteString("\npresentation = ")
+            data.WriteString(row.Presentation)
+         }
+         if price := format_price(row); price != "" {
+            data.WriteString("\nprice = ")
+            data.WriteString(price)
+         }
+         if row.Count >= 1 {
+            data.WriteString("\ncount = ")
+            data.WriteString(strconv.Itoa(row.Count))
+         }
+      }
+   }
+   _, err := w.Write(data.Bytes())
+   return err
+}
+
+func (r *Report) Json(w io.Writer) error {
+   type group struct {
+      Key    string `json:"key"`
+      Offers []*Row `json:"offers"`
+   }
+   var value struct {
+      Title  *justWatch.TitleDetails `json:"title,omitempty"`
+      Groups []group                 `json:"groups"`
+   }
+   value.Title = r.Title
+   for _, key := range r.Keys {
+      var offers []*Row
+      for _, enriched := range r.Groups[key] {
+         offers = append(offers, NewRow(key, enriched))
+      }
+      value.Groups = append(value.Groups, group{key, offers})
+   }
+   encoder := json.NewEncoder(w)
+   encoder.SetIndent("", " ")
+   return encoder.Encode(value)
+}
+
+// Csv writes a header, then one row per EnrichedOffer
+func (r *Report) Csv(w io.Writer) error {
+   writer := csv.NewWriter(w)
+   writer.Write([]string{
+      "group", "url", "country", "name", "season", "episode", "provider",
+      "monetization", "presentation", "price", "currency", "count",
+   })
+   for _, row := range r.Rows() {
+      var price string
+      if row.Price > 0 {
+         price = strconv.FormatFloat(row.Price, 'f', 2, 64)
+      }
+      writer.Write([]string{
+         row.Group,
+         row.Url,
+         row.Country,
+         row.Name,
+         strconv.Itoa(row.Season),
+         strconv.Itoa(row.Episode),
+         row.Provider,
+         row.Monetization,
+         row.Presentation,
+         price,
+         row.Currency,
+         strconv.Itoa(row.Count),
+      })
+   }
+   writer.Flush()
+   return writer.Error()
+}
+
+// Html writes a self contained page with a table that sorts when a header is
+// clicked
+func (r *Report) Html(w io.Writer) error {
+   return html_template.Execute(w, map[string]any{
+      "Heading": r.heading(),
+      "Rows":    r.Rows(),
+   })
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "encoding/json"
+   "strings"
+   "testing"
+)
+
+func new_report() *Report {
+   us := &justWatch.Locale{Country: "US", CountryName: "United States"}
+   gb := &justWatch.Locale{Country: "GB", CountryName: "United Kingdom"}
+   var title justWatch.TitleDetails
+   title.Content.Title = "Goodfellas"
+   title.Content.OriginalReleaseYear = 1990
+   const key = "https://www.netflix.com/title/1"
+   return &Report{
+      Title: &title,
+      Keys:  []string{key},
+      Groups: map[string][]*justWatch.EnrichedOffer{
+         key: {
+            {
+               Locale: gb,
+               Offer: &justWatch.Offer{
+                  StandardWebUrl:   key,
+                  MonetizationType: "FLATRATE",
+                  PresentationType: "HD",
+                  Package:          justWatch.Package{ClearName: "Netflix"},
+               },
+               Season: 2,
+            },
+            {
+               Locale: us,
+               Offer: &justWatch.Offer{
+                  StandardWebUrl:   "https://www.netflix.com/us/title/1?source=35",
+                  MonetizationType: "RENT",
+                  RetailPriceValue: 3.99,
+                  Currency:         "USD",
+                  Package:          justWatch.Package{ClearName: "Netflix"},
+               },
+            },
+         },
+      },
+   }
+}
+
+func TestWrite(t *testing.T) {
+   for _, test := range []struct {
+      format string
+      want   string
+   }{
+      {
+         format: "markdown",
+         want: `# Goodfellas (1990)
+
+## https://www.netflix.com/title/1
+
+country = GB
+name = United Kingdom
+season = 2
+provider = Netflix
+monetization = FLATRATE
+presentation = HD
+
+country = US
+name = United States
+provider = Netflix
+monetization = RENT
+price = 3.99 USD`,
+      },
+      {
+         format: "csv",
+         want: `group,url,country,name,season,episode,provider,monetization,presentation,price,currency,count
+https://www.netflix.com/title/1,https://www.netflix.com/title/1,GB,United Kingdom,2,0,Netflix,FLATRATE,HD,,,0
+https://www.netflix.com/title/1,https://www.netflix.com/us/title/1?source=35,US,United States,0,0,Netflix,RENT,,3.99,USD,0
+`,
+      },
+   } {
+      var data strings.Builder
+      err := new_report().Write(&data, test.format)
+      if err != nil {
+         t.Fatal(err)
+      }
+      if data.String() != test.want {
+         t.Fatalf("%v\n%v", test.format, data.String())
+      }
+   }
+}
+
+func TestJson(t *testing.T) {
+   var data strings.Builder
+   err := new_report().Json(&data)
+   if err != nil {
+      t.Fatal(err)
+   }
+   var value struct {
+      Title struct {
+         Content struct {
+            Title string `json:"title"`
+         } `json:"content"`
+      } `json:"title"`
+      Groups []struct {
+         Key    string `json:"key"`
+         Offers []map[string]any
+      } `json:"groups"`
+   }
+   err = json.Unmarshal([]byte(data.String()), &value)
+   if err != nil {
+      t.Fatal(err)
+   }
+   if value.Title.Content.Title != "Goodfellas" {
+      t.Fatal(data.String())
+   }
+   if !strings.Contains(data.String(), `"content": {`) {
+      t.Fatal("title is not lower case")
+   }
+   if len(value.Groups) != 1 || len(value.Groups[0].Offers) != 2 {
+      t.Fatal(value.Groups)
+   }
+   offer := value.Groups[0].Offers[1]
+   if offer["country"] != "US" || offer["price"] != 3.99 {
+      t.Fatal(offer)
+   }
+   if _, ok := offer["season"]; ok {
+      t.Fatal("season is not omitted")
+   }
+}
+
+func TestHtml(t *testing.T) {
+   var data strings.Builder
+   err := new_report().Html(&data)
+   if err != nil {
+      t.Fatal(err)
+   }
+   for _, value := range []string{
+      "<h1>Goodfellas (1990)</h1>", "<td>United Kingdom</td>", "<td>RENT</td>",
+      `href="https://www.netflix.com/us/title/1?source=35"`,
+   } {
+      if !strings.Contains(data.String(), value) {
+         t.Fatal(value)
+      }
+   }
+}
+
+func TestWriteUnknown(t *testing.T) {
+   if new_report().Write(&strings.Builder{}, "pdf") == nil {
+      t.Fatal("pdf")
+   }
+}
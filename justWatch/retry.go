@@ -0,0 +1,108 @@
+package justWatch
+
+import (
+   "errors"
+   "math"
+   "math/rand/v2"
+   "net/http"
+   "strconv"
+   "strings"
+   "time"
+)
+
+var (
+   ErrRateLimited = errors.New("rate limited")
+   ErrServer      = errors.New("server error")
+)
+
+// StatusError is returned for any response other than 200. Use errors.Is
+// with ErrRateLimited or ErrServer to check the kind
+type StatusError struct {
+   StatusCode int
+   Status     string
+   Body       string
+   RetryAfter time.Duration // zero if the header was missing
+}
+
+func (s *StatusError) Error() string {
+   body := strings.TrimSpace(s.Body)
+   if body == "" {
+      return s.Status
+   }
+   return s.Status + ": " + body
+}
+
+func (s *StatusError) Is(target error) bool {
+   switch target {
+   case ErrRateLimited:
+      return s.StatusCode == http.StatusTooManyRequests
+   case ErrServer:
+      return s.StatusCode >= 500
+   }
+   return false
+}
+
+// both forms from RFC 9110 10.2.3
+func parse_retry_after(value string) time.Duration {
+   if value == "" {
+      return 0
+   }
+   if seconds, err := strconv.Atoi(value); err == nil {
+      return time.Duration(max(seconds, 0)) * time.Second
+   }
+   if date, err := http.ParseTime(value); err == nil {
+      return max(time.Until(date), 0)
+   }
+   return 0
+}
+
+// RetryPolicy retries requests that fail with ErrRateLimited or ErrServer
+type RetryPolicy struct {
+   MaxAttempts int           // including the first, <= 1 for no retries
+   BaseDelay   time.Duration // backoff before the second attempt
+   MaxDelay    time.Duration // <= 0 for no cap
+   // OnRetry, if set, is called before sleeping. attempt is the attempt that
+   // just failed, starting at 1
+   OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+   MaxAttempts: 5,
+   BaseDelay:   time.Second,
+   MaxDelay:    time.Minute,
+}
+
+func (r *RetryPolicy) retry(attempt int, err error) bool {
+   if attempt >= r.MaxAttempts {
+      return false
+   }
+   return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer)
+}
+
+// Retry-After wins if the server sent it. Otherwise the delay doubles every
+// attempt, and a random half of it is jitter. Both are capped by MaxDelay
+func (r *RetryPolicy) delay(attempt int, err error) time.Duration {
+   if status, ok := errors.AsType[*StatusError](err); ok {
+      if status.RetryAfter >= 1 {
+         if r.MaxDelay >= 1 {
+            return min(status.RetryAfter, r.MaxDelay)
+         }
+         return status.RetryAfter
+      }
+   }
+   delay := r.BaseDelay
+   // doubling stops before it can overflow
+   for range attempt - 1 {
+      if delay > math.MaxInt64/2 || r.MaxDelay >= 1 && delay >= r.MaxDelay {
+         break
+      }
+      delay *= 2
+   }
+   if r.MaxDelay >= 1 && delay > r.MaxDelay {
+      delay = max(r.MaxDelay, r.BaseDelay)
+   }
+   if delay <= 1 {
+      return delay
+   }
+   return delay/2 + rand.N(delay/2)
+}
@@ -0,0 +1,67 @@
+package justWatch
+
+import (
+   "context"
+   _ "embed"
+   "strings"
+)
+
+//go:embed GetSearchTitles.gql
+var get_search_titles string
+
+// Country returns the locale for a country code such as "US"
+func (l Locales) Country(code string) (*Locale, bool) {
+   for _, locale_data := range l {
+      if strings.EqualFold(locale_data.Country, code) {
+         return &locale_data, true
+      }
+   }
+   return nil, false
+}
+
+// Search is a wrapper for DefaultClient.Search
+func Search(query, country string) ([]TitleDetails, error) {
+   return DefaultClient.Search(query, country)
+}
+
+// Search returns titles matching query in country, for example "US". Only
+// Id, ObjectId, ObjectType and Content.Title, Content.OriginalReleaseYear,
+// Content.FullPath and Content.ExternalIds are set. Content.FullPath can be passed to
+// Content.Fetch
+func (c *Client) Search(query, country string) ([]TitleDetails, error) {
+   return c.SearchContext(context.Background(), query, country)
+}
+
+func (c *Client) SearchContext(
+   ctx context.Context, query, country string,
+) ([]TitleDetails, error) {
+   language := "en"
+   if locale_data, ok := EnUs.Country(country); ok {
+      language = locale_data.Language()
+   }
+   var result struct {
+      PopularTitles struct {
+         Edges []struct {
+            Node TitleDetails
+         }
+      }
+   }
+   err := c.graphql(
+      ctx,
+      get_search_titles,
+      map[string]string{
+         "country":     strings.ToUpper(country),
+         "language":    language,
+         "searchQuery": query,
+      },
+      &result,
+   )
+   if err != nil {
+      return nil, err
+   }
+   titles := make([]TitleDetails, len(result.PopularTitles.Edges))
+   for i, edge := range result.PopularTitles.Edges {
+      titles[i] = edge.Node
+   }
+   return titles, nil
+}
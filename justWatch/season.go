@@ -0,0 +1,117 @@
+package justWatch
+
+import (
+   "cmp"
+   "context"
+   _ "embed"
+   "maps"
+   "slices"
+)
+
+//go:embed GetShowSeasons.gql
+var get_show_seasons string
+
+type Season struct {
+   Id       string // tss12345
+   ObjectId int
+   Content  struct {
+      Title        string // Season 1
+      SeasonNumber int
+      FullPath     string // /us/tv-show/twin-peaks/season-1
+   }
+   Offers   []Offer
+   Episodes []Episode
+}
+
+type Episode struct {
+   Id       string // tse12345
+   ObjectId int
+   Content  struct {
+      Title         string
+      SeasonNumber  int
+      EpisodeNumber int
+   }
+   Offers []Offer // often empty, JustWatch does not track every episode
+}
+
+// Seasons is a wrapper for DefaultClient.Seasons
+func (h *HrefLangTag) Seasons(localeVar *Locale) ([]Season, error) {
+   return DefaultClient.Seasons(h, localeVar)
+}
+
+// Seasons returns the seasons of the show at tag, each with its offers and
+// episodes. A movie has no seasons
+func (c *Client) Seasons(tag *HrefLangTag, localeVar *Locale) ([]Season, error) {
+   return c.SeasonsContext(context.Background(), tag, localeVar)
+}
+
+func (c *Client) SeasonsContext(
+   ctx context.Context, tag *HrefLangTag, localeVar *Locale,
+) ([]Season, error) {
+   var result struct {
+      Url struct {
+         Node struct {
+            Seasons []Season
+         }
+      }
+   }
+   err := c.graphql(
+      ctx,
+      get_show_seasons+offer_fields,
+      map[string]string{
+         "country":  localeVar.Country,
+         "fullPath": tag.Href,
+         "language": localeVar.Language(),
+      },
+      &result,
+   )
+   if err != nil {
+      return nil, err
+   }
+   return result.Url.Node.Seasons, nil
+}
+
+// SeasonOffers enriches the season and episode offers with localeVar and
+// their season and episode numbers
+func SeasonOffers(localeVar *Locale, seasons []Season) []*EnrichedOffer {
+   var enriched []*EnrichedOffer
+   for _, season := range seasons {
+      for i := range season.Offers {
+         enriched = append(enriched, &EnrichedOffer{
+            Locale: localeVar,
+            Offer:  &season.Offers[i],
+            Season: season.Content.SeasonNumber,
+         })
+      }
+      for _, episode := range season.Episodes {
+         for i := range episode.Offers {
+            enriched = append(enriched, &EnrichedOffer{
+               Locale:  localeVar,
+               Offer:   &episode.Offers[i],
+               Season:  season.Content.SeasonNumber,
+               Episode: episode.Content.EpisodeNumber,
+            })
+         }
+      }
+   }
+   return enriched
+}
+
+// GroupBySeason returns the season numbers in order, and the offers of each
+// season sorted by episode then country. Season 0 holds the offers for the
+// whole title
+func GroupBySeason(offers []*EnrichedOffer) ([]int, map[int][]*EnrichedOffer) {
+   groupedOffers := make(map[int][]*EnrichedOffer)
+   for _, offer := range offers {
+      groupedOffers[offer.Season] = append(groupedOffers[offer.Season], offer)
+   }
+   for _, offerGroup := range groupedOffers {
+      slices.SortFunc(offerGroup, func(a, b *EnrichedOffer) int {
+         return cmp.Or(
+            a.Episode-b.Episode,
+            cmp.Compare(a.Locale.Country, b.Locale.Country),
+         )
+      })
+   }
+   return slices.Sorted(maps.Keys(groupedOffers)), groupedOffers
+}
@@ -0,0 +1,90 @@
+package justWatch
+
+import (
+   "context"
+   _ "embed"
+   "strings"
+)
+
+//go:embed GetTitleDetails.gql
+var get_title_details string
+
+// TitleDetails has JSON tags for the GraphQL field names, so it encodes as
+// it was decoded
+type TitleDetails struct {
+   Id         string `json:"id"`         // tm10
+   ObjectId   int    `json:"objectId"`   // 10
+   ObjectType string `json:"objectType"` // MOVIE, SHOW, SHOW_SEASON
+   Content    struct {
+      Title               string `json:"title"`
+      OriginalTitle       string `json:"originalTitle"`
+      OriginalReleaseYear int    `json:"originalReleaseYear"`
+      Runtime             int    `json:"runtime"`   // minutes
+      FullPath            string `json:"fullPath"`  // /us/movie/goodfellas
+      PosterUrl           string `json:"posterUrl"` // /poster/8620386/{profile}/goodfellas.{format}
+      ExternalIds         struct {
+         ImdbId string `json:"imdbId"` // tt0099685
+         TmdbId string `json:"tmdbId"` // 769
+      } `json:"externalIds"`
+      Genres []struct {
+         ShortName   string `json:"shortName"`   // crm
+         Translation string `json:"translation"` // Crime
+      } `json:"genres"`
+   } `json:"content"`
+}
+
+// Poster returns the full poster address, for example with profile "s718"
+// and format "jpg"
+func (t *TitleDetails) Poster(profile, format string) string {
+   if t.Content.PosterUrl == "" {
+      return ""
+   }
+   poster := strings.NewReplacer(
+      "{profile}", profile, "{format}", format,
+   ).Replace(t.Content.PosterUrl)
+   return "https://images.justwatch.com" + poster
+}
+
+// Language returns the language part of FullLocale, for example "es" for
+// "es_AR"
+func (l *Locale) Language() string {
+   language, _, _ := strings.Cut(l.FullLocale, "_")
+   return language
+}
+
+// TitleDetails is a wrapper for DefaultClient.TitleDetails
+func (h *HrefLangTag) TitleDetails(localeVar *Locale) (*TitleDetails, error) {
+   return DefaultClient.TitleDetails(h, localeVar)
+}
+
+// TitleDetails returns the metadata of the title at tag, in the country and
+// language of localeVar
+func (c *Client) TitleDetails(
+   tag *HrefLangTag, localeVar *Locale,
+) (*TitleDetails, error) {
+   return c.TitleDetailsContext(context.Background(), tag, localeVar)
+}
+
+func (c *Client) TitleDetailsContext(
+   ctx context.Context, tag *HrefLangTag, localeVar *Locale,
+) (*TitleDetails, error) {
+   var result struct {
+      Url struct {
+         Node TitleDetails
+      }
+   }
+   err := c.graphql(
+      ctx,
+      get_title_details,
+      map[string]string{
+         "country":  localeVar.Country,
+         "fullPath": tag.Href,
+         "language": localeVar.Language(),
+      },
+      &result,
+   )
+   if err != nil {
+      return nil, err
+   }
+   return &result.Url.Node, nil
+}
@@ -0,0 +1,23 @@
+package justWatch
+
+// seen on justwatch.com. go generate replaces this with the Translations of
+// every locale
+var KnownTranslations = Translations{
+   "ar": {Provider: "", Movie: "pelicula", Show: ""},
+   "at": {Provider: "anbieter", Movie: "film", Show: "serie"},
+   "au": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+   "be": {Provider: "plateforme", Movie: "", Show: ""},
+   "ca": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+   "ch": {Provider: "anbieter", Movie: "film", Show: "serie"},
+   "cz": {Provider: "poskytovatel", Movie: "", Show: ""},
+   "de": {Provider: "anbieter", Movie: "film", Show: "serie"},
+   "fr": {Provider: "plateforme", Movie: "film", Show: "serie"},
+   "ie": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+   "in": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+   "nl": {Provider: "provider", Movie: "", Show: ""},
+   "nz": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+   "se": {Provider: "leverantör", Movie: "", Show: ""},
+   "uk": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+   "us": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+   "za": {Provider: "provider", Movie: "movie", Show: "tv-show"},
+}
@@ -0,0 +1,370 @@
+// Package watch polls a watchlist of JustWatch titles and notifies when an
+// offer becomes available
+package watch
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "bytes"
+   "context"
+   "encoding/json"
+   "errors"
+   "fmt"
+   "io"
+   "io/fs"
+   "net/http"
+   "os"
+   "path/filepath"
+   "slices"
+   "strings"
+   "sync"
+   "time"
+)
+
+// Rule matches offers. Empty fields match anything
+type Rule struct {
+   Countries         []string // US
+   MonetizationTypes []string // FLATRATE
+}
+
+func (r *Rule) match(enriched *justWatch.EnrichedOffer) bool {
+   if len(r.Countries) >= 1 {
+      if !slices.Contains(r.Countries, enriched.Locale.Country) {
+         return false
+      }
+   }
+   if len(r.MonetizationTypes) >= 1 {
+      if !slices.Contains(r.MonetizationTypes, enriched.Offer.MonetizationType) {
+         return false
+      }
+   }
+   return true
+}
+
+type Watchlist struct {
+   Urls  []string // https://justwatch.com/us/movie/goodfellas
+   Rules []Rule   // an offer matching any rule is watched, empty for all
+}
+
+func ReadWatchlist(name string) (*Watchlist, error) {
+   data, err := os.ReadFile(name)
+   if err != nil {
+      return nil, err
+   }
+   var list Watchlist
+   err = json.Unmarshal(data, &list)
+   if err != nil {
+      return nil, err
+   }
+   return &list, nil
+}
+
+func (w *Watchlist) match(enriched *justWatch.EnrichedOffer) bool {
+   if len(w.Rules) == 0 {
+      return true
+   }
+   for _, rule := range w.Rules {
+      if rule.match(enriched) {
+         return true
+      }
+   }
+   return false
+}
+
+type Notification struct {
+   Time             time.Time
+   Url              string // from the watchlist
+   Key              justWatch.OfferKey
+   MonetizationType string
+   Offer            *justWatch.EnrichedOffer
+}
+
+func (n *Notification) String() string {
+   return n.Url + ": " + n.Key.String() + " " + n.MonetizationType
+}
+
+type Sink interface {
+   Notify(context.Context, *Notification) error
+}
+
+// WriterSink writes one line per notification, for example to os.Stdout
+type WriterSink struct {
+   W io.Writer
+}
+
+func (w *WriterSink) Notify(_ context.Context, n *Notification) error {
+   _, err := io.WriteString(
+      w.W, n.Time.Format(time.DateTime)+" "+n.String()+"\n",
+   )
+   return err
+}
+
+// FileSink appends one JSON line per notification
+type FileSink struct {
+   Name string
+}
+
+func (f *FileSink) Notify(_ context.Context, n *Notification) error {
+   data, err := json.Marshal(n)
+   if err != nil {
+      return err
+   }
+   file, err := os.OpenFile(
+      f.Name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.ModePerm,
+   )
+   if err != nil {
+      return err
+   }
+   defer file.Close()
+   _, err = file.Write(append(data, '\n'))
+   return err
+}
+
+// WebhookSink posts each notification as JSON
+type WebhookSink struct {
+   Url    string
+   Client *http.Client // nil for http.DefaultClient
+}
+
+func (w *WebhookSink) Notify(ctx context.Context, n *Notification) error {
+   data, err := json.Marshal(n)
+   if err != nil {
+      return err
+   }
+   req, err := http.NewRequestWithContext(
+      ctx, "POST", w.Url, bytes.NewReader(data),
+   )
+   if err != nil {
+      return err
+   }
+   req.Header.Set("content-type", "application/json")
+   client := w.Client
+   if client == nil {
+      client = http.DefaultClient
+   }
+   resp, err := client.Do(req)
+   if err != nil {
+      return err
+   }
+   defer resp.Body.Close()
+   if resp.StatusCode >= 300 {
+      return errors.New(resp.Status)
+   }
+   return nil
+}
+
+// State is what was available at the last poll, by watchlist URL
+type State struct {
+   Available map[string][]string
+}
+
+func read_state(name string) (*State, error) {
+   data, err := os.ReadFile(name)
+   if errors.Is(err, fs.ErrNotExist) {
+      return &State{Available: map[string][]string{}}, nil
+   }
+   if err != nil {
+      return nil, err
+   }
+   var state State
+   err = json.Unmarshal(data, &state)
+   if err != nil {
+      return nil, err
+   }
+   if state.Available == nil {
+      state.Available = map[string][]string{}
+   }
+   return &state, nil
+}
+
+func (s *State) write(name string) error {
+   data, err := json.MarshalIndent(s, "", " ")
+   if err != nil {
+      return err
+   }
+   err = os.MkdirAll(filepath.Dir(name), os.ModePerm)
+   if err != nil {
+      return err
+   }
+   temp := name + ".tmp"
+   err = os.WriteFile(temp, data, os.ModePerm)
+   if err != nil {
+      return err
+   }
+   return os.Rename(temp, name)
+}
+
+type Monitor struct {
+   Client    *justWatch.Client // nil for justWatch.DefaultClient
+   Locales   justWatch.Locales
+   Fetch     *justWatch.FetchOptions
+   Watchlist *Watchlist
+   Sinks     []Sink
+   StateFile string
+   Interval  time.Duration // <= 0 for DefaultInterval
+   // NotifyExisting also notifies about offers found the first time a URL
+   // is polled. Otherwise the first poll only records them
+   NotifyExisting bool
+   // OnError, if set, is called with errors from Run that do not stop it
+   OnError func(error)
+   mu      sync.Mutex
+}
+
+func (m *Monitor) client() *justWatch.Client {
+   if m.Client != nil {
+      return m.Client
+   }
+   return &justWatch.DefaultClient
+}
+
+func (m *Monitor) on_error(err error) {
+   if m.OnError != nil {
+      m.OnError(err)
+   }
+}
+
+// DefaultInterval is used when Monitor.Interval is not set
+const DefaultInterval = 6 * time.Hour
+
+// Run polls every Interval until ctx is done
+func (m *Monitor) Run(ctx context.Context) error {
+   for {
+      err := m.Poll(ctx)
+      if ctx.Err() != nil {
+         return ctx.Err()
+      }
+      if err != nil {
+         m.on_error(err)
+      }
+      interval := m.Interval
+      if interval <= 0 {
+         interval = DefaultInterval
+      }
+      timer := time.NewTimer(interval)
+      select {
+      case <-ctx.Done():
+         timer.Stop()
+         return ctx.Err()
+      case <-timer.C:
+      }
+   }
+}
+
+// Poll checks every watchlist URL once, notifies the sinks and saves the
+// state. A failed URL keeps its previous state, as does a failed country of
+// a URL
+func (m *Monitor) Poll(ctx context.Context) error {
+   m.mu.Lock()
+   defer m.mu.Unlock()
+   state, err := read_state(m.StateFile)
+   if err != nil {
+      return err
+   }
+   var errs []error
+   for _, address := range m.Watchlist.Urls {
+      notifications, err := m.poll(ctx, state, address)
+      if err != nil {
+         errs = append(errs, fmt.Errorf("%v: %w", address, err))
+      }
+      for _, n := range notifications {
+         for _, sink := range m.Sinks {
+            err := sink.Notify(ctx, n)
+            if err != nil {
+               errs = append(errs, err)
+            }
+         }
+      }
+   }
+   err = state.write(m.StateFile)
+   if err != nil {
+      errs = append(errs, err)
+   }
+   return errors.Join(errs...)
+}
+
+func (m *Monitor) poll(
+   ctx context.Context, state *State, address string,
+) ([]*Notification, error) {
+   url_path, err := justWatch.GetPath(address)
+   if err != nil {
+      return nil, err
+   }
+   content, err := m.client().ContentContext(ctx, url_path)
+   if err != nil {
+      return nil, err
+   }
+   locales := m.Locales
+   if locales == nil {
+      locales = justWatch.EnUs
+   }
+   offers, fetch_err := m.client().FetchOffers(ctx, content, locales, m.Fetch)
+   if ctx.Err() != nil {
+      return nil, ctx.Err()
+   }
+   failed, err := failed_countries(fetch_err, locales)
+   if err != nil {
+      return nil, err
+   }
+   previous, polled := state.Available[address]
+   var (
+      available     []string
+      notifications []*Notification
+      now           = time.Now().UTC()
+   )
+   for _, enriched := range justWatch.Deduplicate(offers) {
+      if !m.Watchlist.match(enriched) {
+         continue
+      }
+      key := justWatch.NewOfferKey(enriched)
+      id := key.String() + " " + enriched.Offer.MonetizationType
+      if slices.Contains(available, id) {
+         continue
+      }
+      available = append(available, id)
+      if slices.Contains(previous, id) {
+         continue
+      }
+      if polled || m.NotifyExisting {
+         notifications = append(notifications, &Notification{
+            Time:             now,
+            Url:              address,
+            Key:              key,
+            MonetizationType: enriched.Offer.MonetizationType,
+            Offer:            enriched,
+         })
+      }
+   }
+   // a failed country would otherwise look like lost offers
+   for _, id := range previous {
+      country, _, _ := strings.Cut(id, " ")
+      if slices.Contains(failed, country) && !slices.Contains(available, id) {
+         available = append(available, id)
+      }
+   }
+   slices.Sort(available)
+   state.Available[address] = available
+   return notifications, fetch_err
+}
+
+// failed_countries returns the countries of the *justWatch.LocaleError
+// joined into err. Any other error is returned
+func failed_countries(err error, locales justWatch.Locales) ([]string, error) {
+   if err == nil {
+      return nil, nil
+   }
+   joined, ok := err.(interface{ Unwrap() []error })
+   if !ok {
+      return nil, err
+   }
+   var countries []string
+   for _, err := range joined.Unwrap() {
+      locale_err, ok := errors.AsType[*justWatch.LocaleError](err)
+      if !ok {
+         return nil, err
+      }
+      // a tag without a country never had offers
+      if locale, ok := locales.Lookup(&locale_err.Tag); ok {
+         countries = append(countries, locale.Country)
+      }
+   }
+   return countries, nil
+}
@@ -0,0 +1,151 @@
+package watch
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "context"
+   "encoding/json"
+   "errors"
+   "fmt"
+   "net/http"
+   "net/http/httptest"
+   "path/filepath"
+   "strings"
+   "sync/atomic"
+   "testing"
+   "time"
+)
+
+func TestPoll(t *testing.T) {
+   monetization := "RENT"
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         if req.URL.Path == "/content/urls" {
+            fmt.Fprint(w, `{"href_lang_tags":[{"locale":"en_US"}]}`)
+            return
+         }
+         fmt.Fprintf(w, `{"data":{"url":{"node":{"offers":[{
+            "standardWebURL": "https://a.com/1", "monetizationType": %q
+         }]}}}}`, monetization)
+      },
+   ))
+   defer server.Close()
+   var notified strings.Builder
+   monitor := Monitor{
+      Client: &justWatch.Client{BaseUrl: server.URL},
+      Watchlist: &Watchlist{
+         Urls: []string{"https://justwatch.com/us/movie/goodfellas"},
+         Rules: []Rule{
+            {Countries: []string{"US"}, MonetizationTypes: []string{"FLATRATE"}},
+         },
+      },
+      Sinks:     []Sink{&WriterSink{W: &notified}},
+      StateFile: filepath.Join(t.TempDir(), "state.json"),
+   }
+   // first poll only records, then FLATRATE appears, stays, goes and
+   // comes back
+   for _, monetization = range []string{
+      "RENT", "FLATRATE", "FLATRATE", "RENT", "FLATRATE",
+   } {
+      err := monitor.Poll(context.Background())
+      if err != nil {
+         t.Fatal(err)
+      }
+   }
+   if strings.Count(notified.String(), "\n") != 2 {
+      t.Fatal(notified.String())
+   }
+}
+
+func TestPollPartial(t *testing.T) {
+   var (
+      gb_fails bool
+      extra    string
+   )
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         if req.URL.Path == "/content/urls" {
+            fmt.Fprint(w, `{"href_lang_tags":[
+               {"locale":"en_US","href":"/us/movie/goodfellas"},
+               {"locale":"en_GB","href":"/uk/movie/goodfellas"},
+               {"locale":"bad","href":"/xx/movie/goodfellas"}
+            ]}`)
+            return
+         }
+         var body struct {
+            Variables struct {
+               Country string
+            }
+         }
+         json.NewDecoder(req.Body).Decode(&body)
+         if body.Variables.Country == "GB" && gb_fails {
+            fmt.Fprint(w, `{"errors":[{
+               "message":"country not found",
+               "extensions":{"code":"BAD_USER_INPUT"}
+            }]}`)
+            return
+         }
+         fmt.Fprintf(w, `{"data":{"url":{"node":{"offers":[
+            {"standardWebURL":"https://a.com/1","monetizationType":"FLATRATE"}%v
+         ]}}}}`, extra)
+      },
+   ))
+   defer server.Close()
+   var notified strings.Builder
+   monitor := Monitor{
+      Client: &justWatch.Client{BaseUrl: server.URL},
+      Watchlist: &Watchlist{
+         Urls: []string{"https://justwatch.com/us/movie/goodfellas"},
+      },
+      Sinks:     []Sink{&WriterSink{W: &notified}},
+      StateFile: filepath.Join(t.TempDir(), "state.json"),
+   }
+   // GB fails while b.com appears, then GB comes back unchanged
+   for i, value := range []struct {
+      gb_fails bool
+      extra    string
+   }{
+      {false, ""},
+      {true, `,{"standardWebURL":"https://b.com/1","monetizationType":"ADS"}`},
+      {false, `,{"standardWebURL":"https://b.com/1","monetizationType":"ADS"}`},
+   } {
+      gb_fails, extra = value.gb_fails, value.extra
+      err := monitor.Poll(t.Context())
+      if _, ok := errors.AsType[*justWatch.LocaleError](err); !ok {
+         t.Fatal(i, err)
+      }
+   }
+   // GB a.com was kept while GB failed, so only b.com is new
+   lines := strings.Split(strings.TrimSpace(notified.String()), "\n")
+   if len(lines) != 2 ||
+      !strings.HasSuffix(lines[0], "US https://b.com/1 ADS") ||
+      !strings.HasSuffix(lines[1], "GB https://b.com/1 ADS") {
+      t.Fatal(notified.String())
+   }
+}
+
+func TestRunInterval(t *testing.T) {
+   var polls atomic.Int32
+   server := httptest.NewServer(http.HandlerFunc(
+      func(w http.ResponseWriter, req *http.Request) {
+         if req.URL.Path == "/content/urls" {
+            polls.Add(1)
+         }
+         fmt.Fprint(w, `{"href_lang_tags":[]}`)
+      },
+   ))
+   defer server.Close()
+   monitor := Monitor{
+      Client: &justWatch.Client{BaseUrl: server.URL},
+      Watchlist: &Watchlist{
+         Urls: []string{"https://justwatch.com/us/movie/goodfellas"},
+      },
+      StateFile: filepath.Join(t.TempDir(), "state.json"),
+   }
+   ctx, cancel := context.WithTimeout(t.Context(), 99*time.Millisecond)
+   defer cancel()
+   monitor.Run(ctx)
+   // the zero Interval waits DefaultInterval, so there is only one poll
+   if polls.Load() != 1 {
+      t.Fatal(polls.Load())
+   }
+}
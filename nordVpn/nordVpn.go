@@ -1,6 +1,7 @@
 package nordVpn
 
 import (
+   "context"
    "encoding/json"
    "io"
    "net/http"
@@ -12,6 +13,10 @@ import (
 // limit <= -1 for default
 // limit == 0 for all
 func WriteServers(limit int) ([]byte, error) {
+   return WriteServersContext(context.Background(), limit)
+}
+
+func WriteServersContext(ctx context.Context, limit int) ([]byte, error) {
    var req http.Request
    req.URL = &url.URL{
       Scheme: "https",
@@ -22,7 +27,7 @@ func WriteServers(limit int) ([]byte, error) {
       req.URL.RawQuery = "limit=" + strconv.Itoa(limit)
    }
    req.Header = http.Header{}
-   resp, err := http.DefaultClient.Do(&req)
+   resp, err := http.DefaultClient.Do(req.WithContext(ctx))
    if err != nil {
       return nil, err
    }
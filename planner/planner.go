@@ -0,0 +1,140 @@
+// Package planner finds where a JustWatch title is included with the
+// providers you subscribe to, and the NordVPN proxies to watch it from there
+package planner
+
+import (
+   "41.neocities.org/verde/justWatch"
+   "41.neocities.org/verde/nordVpn"
+   "cmp"
+   "context"
+   "maps"
+   "slices"
+   "strings"
+)
+
+type Options struct {
+   Providers    []string // technical or clear names, such as netflix or MUBI
+   Monetization []string // nil for FLATRATE, FREE and ADS
+   Preference   []string // countries to rank first, in order, such as US,GB
+   Proxies      int      // proxies per country, zero for all
+}
+
+// Country is a country where the title is included
+type Country struct {
+   Code    string // US
+   Name    string // United States
+   Offers  []*justWatch.EnrichedOffer
+   Proxies []string // from nordVpn.FormatProxy
+}
+
+type Planner struct {
+   JustWatch          *justWatch.Client // nil for justWatch.DefaultClient
+   Locales            justWatch.Locales // nil for justWatch.EnUs
+   Fetch              *justWatch.FetchOptions
+   Servers            []nordVpn.Server
+   Username, Password string
+}
+
+// Plan fetches the offers of the title at address, a justwatch.com URL, and
+// ranks them as Rank does. The countries that could be fetched are returned
+// even if others failed, with the error of justWatch.Client.FetchOffers
+func (p *Planner) Plan(
+   ctx context.Context, address string, opts *Options,
+) ([]*Country, error) {
+   client := p.JustWatch
+   if client == nil {
+      client = &justWatch.DefaultClient
+   }
+   locales := p.Locales
+   if locales == nil {
+      locales = justWatch.EnUs
+   }
+   url_path, err := justWatch.GetPath(address)
+   if err != nil {
+      return nil, err
+   }
+   content, err := client.ContentContext(ctx, url_path)
+   if err != nil {
+      return nil, err
+   }
+   offers, err := client.FetchOffers(ctx, content, locales, p.Fetch)
+   if ctx.Err() != nil {
+      return nil, ctx.Err()
+   }
+   return Rank(offers, p.Servers, p.Username, p.Password, opts), err
+}
+
+// presentation types, best last
+var presentations = []string{"SD", "HD", "_4K"}
+
+func best_presentation(offers []*justWatch.EnrichedOffer) int {
+   best := -1
+   for _, offer := range offers {
+      best = max(best, slices.Index(presentations, offer.Offer.PresentationType))
+   }
+   return best
+}
+
+// Rank returns the countries with an offer from opts.Providers, best first:
+// countries in opts.Preference in that order, then countries with a proxy,
+// then by best presentation type, then by number of offers, then by code.
+// Proxies are from servers where ProxySsl and Country match
+func Rank(
+   offers []*justWatch.EnrichedOffer, servers []nordVpn.Server,
+   username, password string, opts *Options,
+) []*Country {
+   types := opts.Monetization
+   if types == nil {
+      types = []string{"FLATRATE", "FREE", "ADS"}
+   }
+   offers = justWatch.Filter(offers, justWatch.And(
+      justWatch.Provider(opts.Providers...),
+      justWatch.Monetization(types...),
+   ))
+   countries := map[string]*Country{}
+   for _, offer := range offers {
+      code := offer.Locale.Country
+      if countries[code] == nil {
+         countries[code] = &Country{
+            Code: code, Name: offer.Locale.CountryName,
+         }
+      }
+      countries[code].Offers = append(countries[code].Offers, offer)
+   }
+   for _, country := range countries {
+      for _, server := range servers {
+         if opts.Proxies >= 1 && len(country.Proxies) >= opts.Proxies {
+            break
+         }
+         if server.ProxySsl() && server.Country(country.Code) {
+            country.Proxies = append(country.Proxies, nordVpn.FormatProxy(
+               username, password, server.Hostname,
+            ))
+         }
+      }
+   }
+   preference := func(code string) int {
+      i := slices.IndexFunc(opts.Preference, func(v string) bool {
+         return strings.EqualFold(v, code)
+      })
+      if i == -1 {
+         return len(opts.Preference)
+      }
+      return i
+   }
+   has_proxy := func(c *Country) int {
+      if len(c.Proxies) >= 1 {
+         return 0
+      }
+      return 1
+   }
+   return slices.SortedFunc(maps.Values(countries), func(a, b *Country) int {
+      return cmp.Or(
+         preference(a.Code)-preference(b.Code),
+         has_proxy(a)-has_proxy(b),
+         best_presentation(b.Offers)-best_presentation(a.Offers),
+         len(b.Offers)-len(a.Offers),
+         cmp.Compare(a.Code, b.Code),
+      )
+   })
+}